@@ -0,0 +1,307 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"strings"
+
+	"githubc.com/asadbekGo/generate-code/config"
+	"githubc.com/asadbekGo/generate-code/handlers"
+	"githubc.com/asadbekGo/generate-code/pkg/helper"
+	"githubc.com/asadbekGo/generate-code/pkg/parser"
+	"githubc.com/asadbekGo/generate-code/pkg/writer"
+	"githubc.com/asadbekGo/generate-code/protos"
+	"githubc.com/asadbekGo/generate-code/schema"
+	"githubc.com/asadbekGo/generate-code/storage"
+)
+
+// generateFlags are the flags shared by every command that writes code.
+type generateFlags struct {
+	cfg         config.GenerateConfig
+	only        string
+	tables      string
+	exclude     string
+	projectFile string
+}
+
+func newGenerateFlags(flags *flag.FlagSet) *generateFlags {
+	var f = &generateFlags{}
+
+	flags.StringVar(&f.projectFile, "config", config.DefaultProjectFile, "project configuration file with module paths and package names")
+	flags.StringVar(&f.cfg.OutputDir, "output", "./generates", "output root directory")
+	flags.StringVar(&f.cfg.TemplateDir, "templates", ".", "template root directory containing handlers/, protos/ and storage/")
+	flags.StringVar(&f.only, "only", "", "comma separated generators to run: "+strings.Join(config.Generators, ","))
+	flags.StringVar(&f.tables, "tables", "", "comma separated glob patterns of the tables to generate, e.g. coming,client_*")
+	flags.StringVar(&f.exclude, "exclude", "", "comma separated glob patterns of the tables to skip")
+	flags.BoolVar(&f.cfg.Force, "force", false, "overwrite existing files")
+	flags.BoolVar(&f.cfg.DryRun, "dry-run", false, "print a unified diff of the changes without writing any file")
+	flags.BoolVar(&f.cfg.Check, "check", false, "exit with an error when generated files are out of date, nothing is written")
+	flags.BoolVar(&f.cfg.AllowBreaking, "allow-breaking", false, "replace protos even when the change breaks deployed clients")
+
+	return f
+}
+
+// config validates the parsed flags and loads the project file.
+func (f *generateFlags) config() (config.GenerateConfig, error) {
+	generators, err := config.ParseGenerators(f.only)
+	if err != nil {
+		return f.cfg, err
+	}
+	f.cfg.Only = generators
+
+	f.cfg.Tables, err = config.ParsePatterns(f.tables)
+	if err != nil {
+		return f.cfg, err
+	}
+
+	f.cfg.Exclude, err = config.ParsePatterns(f.exclude)
+	if err != nil {
+		return f.cfg, err
+	}
+
+	f.cfg.Project, err = config.LoadProject(f.projectFile)
+	if err != nil {
+		return f.cfg, err
+	}
+
+	return f.cfg, nil
+}
+
+func runGenerate(args []string) error {
+
+	var (
+		input      string
+		migrations string
+		flags      = flag.NewFlagSet("generate", flag.ExitOnError)
+		f          = newGenerateFlags(flags)
+	)
+
+	flags.StringVar(&input, "input", "./sql/template.sql", "comma separated SQL input files, positional arguments are appended")
+	flags.StringVar(&migrations, "migrations", "", "directory of numbered *.up.sql migrations to apply instead of -input")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := f.config()
+	if err != nil {
+		return err
+	}
+
+	if flags.NArg() > 0 {
+		cfg.Inputs = flags.Args()
+	} else {
+		cfg.Inputs = strings.Split(input, ",")
+	}
+
+	var s *schema.Schema
+	if migrations != "" {
+		s, err = parser.ParseMigrations(migrations)
+		if err != nil {
+			log.Println("Error while ParseMigrations:", err.Error())
+			return err
+		}
+	} else {
+		s, err = readInputs(cfg.Inputs)
+		if err != nil {
+			return err
+		}
+	}
+
+	return generate(cfg, s)
+}
+
+func readInputs(inputs []string) (*schema.Schema, error) {
+
+	var s = &schema.Schema{}
+
+	for _, input := range inputs {
+		input = strings.TrimSpace(input)
+
+		body, err := helper.ReadFile(input)
+		if err != nil {
+			log.Println("Error while read file:", err.Error())
+			return nil, err
+		}
+
+		err = parser.ParseInto(s, input, string(body))
+		if err != nil {
+			log.Println("Error while Parse:", err.Error())
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+func generate(cfg config.GenerateConfig, s *schema.Schema) error {
+
+	var w = writer.New(cfg.OutputDir, cfg.Force, cfg.DryRun, cfg.Check)
+
+	// api.go, storage.go, the package protos and the index migration hold
+	// every table, the files of a single table are only written for the
+	// tables selected with -tables and -exclude
+	var tables, selected []*schema.Table
+	for _, table := range s.Tables {
+		if len(table.KeyColumns()) == 0 {
+			if cfg.Selected(table.Name) {
+				log.Println("Skip table without primary key:", table.Name)
+			}
+			continue
+		}
+
+		tables = append(tables, table)
+		if cfg.Selected(table.Name) {
+			selected = append(selected, table)
+		}
+	}
+
+	if cfg.Enabled(config.GeneratorProtos) {
+		err := protos.LoadLock(cfg)
+		if err != nil {
+			log.Println("Error while LoadLock:", err.Error())
+			return err
+		}
+
+		err = checkBreaking(cfg, s, tables)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, table := range selected {
+		if cfg.TableEnabled(table.Name, config.GeneratorHandlers) {
+			err := handlers.MakeHandlerss(cfg, w, s, table)
+			if err != nil {
+				log.Println("Error while MakeHandlerss:", err.Error())
+				return err
+			}
+		}
+
+		if cfg.TableEnabled(table.Name, config.GeneratorProtos) {
+			err := protos.MakeProtos(cfg, w, s, table)
+			if err != nil {
+				log.Println("Error while MakeProtos:", err.Error())
+				return err
+			}
+		}
+
+		if cfg.TableEnabled(table.Name, config.GeneratorService) {
+			err := storage.MakeService(cfg, w, s, table)
+			if err != nil {
+				log.Println("Error while MakeService:", err.Error())
+				return err
+			}
+		}
+
+		if cfg.TableEnabled(table.Name, config.GeneratorStorage) {
+			err := storage.MakeStorage(cfg, w, s, table)
+			if err != nil {
+				log.Println("Error while MakeStorage:", err.Error())
+				return err
+			}
+		}
+	}
+
+	for _, table := range tables {
+		if cfg.TableEnabled(table.Name, config.GeneratorHandlers) {
+			err := handlers.AddRoutes(cfg, s, table)
+			if err != nil {
+				log.Println("Error while AddRoutes:", err.Error())
+				return err
+			}
+		}
+
+		if cfg.TableEnabled(table.Name, config.GeneratorProtos) {
+			err := protos.AddServiceProto(cfg, s, table)
+			if err != nil {
+				log.Println("Error while AddServiceProto:", err.Error())
+				return err
+			}
+		}
+
+		if cfg.TableEnabled(table.Name, config.GeneratorStorage) {
+			err := storage.AddStorageRepo(cfg, s, table)
+			if err != nil {
+				log.Println("Error while AddStorageRepo:", err.Error())
+				return err
+			}
+		}
+	}
+
+	if cfg.Enabled(config.GeneratorHandlers) {
+		err := handlers.MakeApi(w)
+		if err != nil {
+			log.Println("Error while MakeApi:", err.Error())
+			return err
+		}
+	}
+
+	if cfg.Enabled(config.GeneratorProtos) {
+		err := protos.MakeServiceProtos(w)
+		if err != nil {
+			log.Println("Error while MakeServiceProtos:", err.Error())
+			return err
+		}
+
+		err = protos.MakeLock(w)
+		if err != nil {
+			log.Println("Error while MakeLock:", err.Error())
+			return err
+		}
+	}
+
+	if cfg.Enabled(config.GeneratorStorage) {
+		err := storage.MakeStorageRepo(w)
+		if err != nil {
+			log.Println("Error while MakeStorageRepo:", err.Error())
+			return err
+		}
+
+		err = storage.MakeForeignKeyIndexes(cfg, w)
+		if err != nil {
+			log.Println("Error while MakeForeignKeyIndexes:", err.Error())
+			return err
+		}
+	}
+
+	if cfg.Check && len(w.Stale) > 0 {
+		for _, path := range w.Stale {
+			log.Println("Out of date:", path)
+		}
+		return fmt.Errorf("%d generated files are out of date", len(w.Stale))
+	}
+
+	return nil
+}
+
+// checkBreaking compares the protos, their enums and common.proto with the
+// files on disk and fails on changes that break deployed clients, unless
+// they are allowed. tables are all tables, BreakingChanges leaves out the
+// files a run with -tables does not write. It runs whatever the flags, so no way of writing the
+// files gets around it.
+func checkBreaking(cfg config.GenerateConfig, s *schema.Schema, tables []*schema.Table) error {
+	var protoTables []*schema.Table
+	for _, table := range tables {
+		if cfg.TableEnabled(table.Name, config.GeneratorProtos) {
+			protoTables = append(protoTables, table)
+		}
+	}
+
+	changes, err := protos.BreakingChanges(cfg, s, protoTables)
+	if err != nil {
+		log.Println("Error while BreakingChanges:", err.Error())
+		return err
+	}
+
+	for _, change := range changes {
+		log.Println("Breaking change:", change)
+	}
+
+	if len(changes) > 0 && !cfg.AllowBreaking {
+		return fmt.Errorf("%d breaking proto changes, rerun with -allow-breaking to accept them", len(changes))
+	}
+
+	return nil
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"githubc.com/asadbekGo/generate-code/config"
+	"githubc.com/asadbekGo/generate-code/pkg/parser"
+)
+
+func TestGenerateSelectedTables(t *testing.T) {
+	s, err := parser.Parse("test.sql", `
+CREATE TABLE client (id UUID PRIMARY KEY, name TEXT NOT NULL);
+CREATE TABLE product (id UUID PRIMARY KEY, client_id UUID REFERENCES client(id), title TEXT NOT NULL);
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var project = config.DefaultProject()
+	project.ProtoLayout = config.ProtoLayoutService
+
+	var cfg = config.GenerateConfig{
+		OutputDir:   t.TempDir(),
+		TemplateDir: "..",
+		Tables:      []string{"client"},
+		Project:     project,
+	}
+
+	if err := generate(cfg, s); err != nil {
+		t.Fatal(err)
+	}
+
+	var files = []struct {
+		path string
+		want []string
+	}{
+		{"handlers/api.go", []string{"CreateClient", "CreateProduct"}},
+		{"storage/client_storage/storage.go", []string{"type ClientRepoI interface", "type ProductRepoI interface"}},
+		{"protos/storehouse_client_service.proto", []string{"message Client {", "message Product {"}},
+		{"migrations/foreign_key_indexes.up.sql", []string{"product_client_id_idx"}},
+	}
+	for _, file := range files {
+		body, err := os.ReadFile(filepath.Join(cfg.OutputDir, file.path))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, want := range file.want {
+			if !strings.Contains(string(body), want) {
+				t.Errorf("%s does not contain %q", file.path, want)
+			}
+		}
+	}
+
+	for path, want := range map[string]bool{
+		"handlers/client_handler/client.go":  true,
+		"handlers/client_handler/product.go": false,
+		"storage/client_storage/product.go":  false,
+		"service/client_service/product.go":  false,
+	} {
+		if _, err := os.Stat(filepath.Join(cfg.OutputDir, path)); (err == nil) != want {
+			t.Errorf("%s written: %v, want %v", path, err == nil, want)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+
+	"githubc.com/asadbekGo/generate-code/config"
+	"githubc.com/asadbekGo/generate-code/pkg/introspect"
+)
+
+// runIntrospect generates code from a live database, the connection is
+// configured with the POSTGRES_* environment variables read by config.Load.
+func runIntrospect(args []string) error {
+
+	var (
+		schemaName string
+		flags      = flag.NewFlagSet("introspect", flag.ExitOnError)
+		f          = newGenerateFlags(flags)
+	)
+
+	flags.StringVar(&schemaName, "schema", "public", "database schema to read the tables from")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := f.config()
+	if err != nil {
+		return err
+	}
+
+	var ctx = context.Background()
+
+	db, err := introspect.Connect(ctx, config.Load())
+	if err != nil {
+		log.Println("Error while Connect:", err.Error())
+		return err
+	}
+	defer db.Close()
+
+	s, err := introspect.Load(ctx, db, schemaName)
+	if err != nil {
+		log.Println("Error while introspect.Load:", err.Error())
+		return err
+	}
+
+	return generate(cfg, s)
+}
@@ -1,62 +1,48 @@
 package main
 
 import (
+	"fmt"
 	"log"
+	"os"
 	"strings"
-
-	"githubc.com/asadbekGo/generate-code/handlers"
-	"githubc.com/asadbekGo/generate-code/pkg/helper"
-	"githubc.com/asadbekGo/generate-code/protos"
-	"githubc.com/asadbekGo/generate-code/storage"
 )
 
+const usage = `Usage: generate-code <command> [flags] [input.sql ...]
+
+Commands:
+  generate    generate protos, storage, service and gateway handlers from SQL files
+  introspect  generate the same code from the tables of a live PostgreSQL database
+  help        show this message
+
+Run "generate-code <command> -h" to see the flags of a command.
+`
+
 func main() {
 
-	body, err := helper.ReadFile("./sql/template.sql")
-	if err != nil {
-		log.Println("Error while read file:", err.Error())
-		return
-	}
+	var (
+		command = "generate"
+		args    = os.Args[1:]
+	)
 
-	tables := strings.Split(string(body), ";")
-	for _, table := range tables {
-		if len(table) > 1 {
-			err = handlers.MakeHandlerss([]byte(table))
-			if err != nil {
-				log.Println("Error while MakeHandlerss:", err.Error())
-				return
-			}
-
-			err = protos.MakeProtos([]byte(table))
-			if err != nil {
-				log.Println("Error while read file:", err.Error())
-				return
-			}
-
-			err = storage.MakeService([]byte(table))
-			if err != nil {
-				log.Println("Error while read file:", err.Error())
-				return
-			}
-
-			err = storage.MakeStorage([]byte(table))
-			if err != nil {
-				log.Println("Error while read file:", err.Error())
-				return
-			}
-		}
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		command, args = args[0], args[1:]
 	}
 
-	err = handlers.MakeApi()
-	if err != nil {
-		log.Println("Error while MakeApi:", err.Error())
-		return
+	var err error
+	switch command {
+	case "generate":
+		err = runGenerate(args)
+	case "introspect":
+		err = runIntrospect(args)
+	case "help":
+		fmt.Print(usage)
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		err = fmt.Errorf("unknown command %q", command)
 	}
 
-	err = storage.MakeStorageRepo()
 	if err != nil {
-		log.Println("Error while MakeStorageRepo:", err.Error())
-		return
+		log.Println("Error:", err.Error())
+		os.Exit(1)
 	}
-
 }
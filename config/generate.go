@@ -0,0 +1,113 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+const (
+	GeneratorHandlers = "handlers"
+	GeneratorProtos   = "protos"
+	GeneratorService  = "service"
+	GeneratorStorage  = "storage"
+)
+
+var Generators = []string{GeneratorHandlers, GeneratorProtos, GeneratorService, GeneratorStorage}
+
+// GenerateConfig holds the command-line options of a generator run.
+type GenerateConfig struct {
+	Inputs      []string
+	OutputDir   string
+	TemplateDir string
+	Only        []string
+	Tables      []string
+	Exclude     []string
+	Force       bool
+	DryRun      bool
+	Check       bool
+	// AllowBreaking writes protos that break deployed clients instead of
+	// failing the run.
+	AllowBreaking bool
+	Project       Project
+}
+
+// ParseGenerators splits a comma separated generator list and checks every name.
+func ParseGenerators(value string) ([]string, error) {
+	var generators []string
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		if !contains(Generators, name) {
+			return nil, fmt.Errorf("unknown generator %q, expected one of %s", name, strings.Join(Generators, ","))
+		}
+		generators = append(generators, name)
+	}
+
+	return generators, nil
+}
+
+// Enabled reports whether the generator was selected with -only.
+// An empty selection means every generator runs.
+func (c GenerateConfig) Enabled(generator string) bool {
+	return len(c.Only) == 0 || contains(c.Only, generator)
+}
+
+// Selected reports whether the table matches -tables and none of -exclude.
+func (c GenerateConfig) Selected(table string) bool {
+	if len(c.Tables) > 0 && !matchAny(c.Tables, table) {
+		return false
+	}
+
+	return !matchAny(c.Exclude, table)
+}
+
+// TableEnabled reports whether the generator runs for the table, taking
+// both -only and the table options of the project file into account.
+func (c GenerateConfig) TableEnabled(table, generator string) bool {
+	if !c.Enabled(generator) {
+		return false
+	}
+
+	var generators = c.Project.Table(table).Generators
+	return len(generators) == 0 || contains(generators, generator)
+}
+
+// ParsePatterns splits a comma separated list of table glob patterns.
+func ParsePatterns(value string) ([]string, error) {
+	var patterns []string
+	for _, pattern := range strings.Split(value, ",") {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid table pattern %q: %w", pattern, err)
+		}
+		patterns = append(patterns, pattern)
+	}
+
+	return patterns, nil
+}
+
+func matchAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(s []string, e string) bool {
+	for _, a := range s {
+		if a == e {
+			return true
+		}
+	}
+	return false
+}
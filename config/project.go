@@ -0,0 +1,243 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"githubc.com/asadbekGo/generate-code/pkg/types"
+	"githubc.com/asadbekGo/generate-code/schema"
+)
+
+const DefaultProjectFile = "generate.json"
+
+// DefaultBatchSize is the number of items a batch request may hold unless
+// the project sets batch.max_size.
+const DefaultBatchSize = 1000
+
+const (
+	// ProtoLayoutTable writes a proto file per table.
+	ProtoLayoutTable = "table"
+	// ProtoLayoutService writes a proto file per proto package holding the
+	// services and messages of all its tables.
+	ProtoLayoutService = "service"
+)
+
+// Project describes the service the generated code is written for. It is
+// read from generate.json, groups share the values of Defaults unless they
+// set their own.
+type Project struct {
+	ServiceModule string                 `json:"service_module"`
+	GatewayModule string                 `json:"gateway_module"`
+	Defaults      Group                  `json:"defaults"`
+	Groups        []Group                `json:"groups"`
+	Tables        map[string]TableConfig `json:"tables"`
+	// Types overrides the proto type of an SQL type, e.g. "numeric": "string".
+	Types map[string]string `json:"types"`
+	// Display lists the columns a referenced row is expanded with, e.g.
+	// "client": ["first_name", "last_name"]. Tables not listed are expanded
+	// with their name column when they have one.
+	Display map[string][]string `json:"display"`
+	// CommonProto moves the pagination, filter and patch fields every list
+	// and patch request declares into messages of a shared common.proto.
+	CommonProto bool `json:"common_proto"`
+	// ProtoLayout is ProtoLayoutTable, the default, or ProtoLayoutService.
+	ProtoLayout string `json:"proto_layout"`
+	// Batch configures the CreateMany, GetByIDs and DeleteMany RPCs.
+	Batch Batch `json:"batch"`
+}
+
+// Batch configures the batch RPCs of a table.
+type Batch struct {
+	// MaxSize is the largest number of items or ids a request may hold.
+	MaxSize int `json:"max_size"`
+	// Partial creates and deletes the items that succeed and reports the
+	// others, by default a batch is all or nothing.
+	Partial bool `json:"partial"`
+}
+
+// Group is a set of tables published under one proto package.
+type Group struct {
+	Name           string   `json:"name"`
+	Tables         []string `json:"tables"`
+	ProtoPackage   string   `json:"proto_package"`
+	GoPackage      string   `json:"go_package"`
+	StoragePackage string   `json:"storage_package"`
+	ServicePackage string   `json:"service_package"`
+	HandlerPackage string   `json:"handler_package"`
+}
+
+// TableConfig narrows what is generated for a single table.
+type TableConfig struct {
+	// Generators lists the artifacts emitted for the table, empty means all.
+	Generators []string `json:"generators"`
+	// ReadOnly keeps only the Get and GetList endpoints.
+	ReadOnly bool `json:"read_only"`
+	// Columns overrides the proto type of single columns, e.g. "price": "string".
+	Columns map[string]string `json:"columns"`
+	// Expand lists the foreign key columns whose referenced row Get and
+	// GetList load on request, e.g. "client_id" adds a nested client field
+	// and "approver" an approver_ref field.
+	Expand []string `json:"expand"`
+	// Join forces the many-to-many join table API on, or with false off,
+	// unset leaves it to the detection of helper.TemplateData.Join.
+	Join *bool `json:"join"`
+	// Batch overrides the batch options of the project, a zero max_size
+	// keeps the project limit.
+	Batch *Batch `json:"batch"`
+}
+
+func DefaultProject() Project {
+	return Project{
+		ServiceModule: "warehouse/warehouse_go_storehouse_service",
+		GatewayModule: "warehouse/warehouse_go_api_gateway",
+		ProtoLayout:   ProtoLayoutTable,
+		Batch:         Batch{MaxSize: DefaultBatchSize},
+		Defaults: Group{
+			Name:           "client",
+			ProtoPackage:   "storehouse_client_service",
+			GoPackage:      "genproto/storehouse_client_service",
+			StoragePackage: "client_storage",
+			ServicePackage: "client_service",
+			HandlerPackage: "client_handler",
+		},
+	}
+}
+
+// LoadProject reads the project file. A missing file is not an error, the
+// defaults are returned instead.
+func LoadProject(filename string) (Project, error) {
+	var project = DefaultProject()
+
+	body, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return project, nil
+	}
+	if err != nil {
+		return project, err
+	}
+
+	err = json.Unmarshal(body, &project)
+	if err != nil {
+		return project, err
+	}
+
+	for name, table := range project.Tables {
+		for _, generator := range table.Generators {
+			if !contains(Generators, generator) {
+				return project, fmt.Errorf("%s: table %s: unknown generator %q", filename, name, generator)
+			}
+		}
+
+		for column, protoType := range table.Columns {
+			if _, ok := types.Go(protoType); !ok {
+				return project, fmt.Errorf("%s: column %s.%s: unsupported proto type %q", filename, name, column, protoType)
+			}
+		}
+
+		if table.Batch != nil && table.Batch.MaxSize < 0 {
+			return project, fmt.Errorf("%s: table %s: batch max_size must not be negative", filename, name)
+		}
+	}
+
+	if project.Batch.MaxSize <= 0 {
+		return project, fmt.Errorf("%s: batch max_size must be positive", filename)
+	}
+
+	if project.ProtoLayout != ProtoLayoutTable && project.ProtoLayout != ProtoLayoutService {
+		return project, fmt.Errorf("%s: unknown proto_layout %q, expected %s or %s", filename, project.ProtoLayout, ProtoLayoutTable, ProtoLayoutService)
+	}
+
+	var sqlTypes = map[string]string{}
+	for sqlType, protoType := range project.Types {
+		if _, ok := types.Go(protoType); !ok {
+			return project, fmt.Errorf("%s: type %s: unsupported proto type %q", filename, sqlType, protoType)
+		}
+		sqlTypes[schema.NormalizeType(strings.ToLower(sqlType))] = protoType
+	}
+	project.Types = sqlTypes
+
+	project.Defaults = project.Defaults.merge(DefaultProject().Defaults)
+
+	return project, nil
+}
+
+// Group returns the group the table belongs to, falling back to Defaults.
+func (p Project) Group(table string) Group {
+	for _, group := range p.Groups {
+		if contains(group.Tables, table) {
+			return group.merge(p.Defaults)
+		}
+	}
+
+	return p.Defaults
+}
+
+// Table returns the per table options, the zero value when none are set.
+func (p Project) Table(table string) TableConfig {
+	return p.Tables[table]
+}
+
+// TableBatch returns the batch options of the table, the project options
+// overridden by those of the table.
+func (p Project) TableBatch(table string) Batch {
+	var batch = p.Table(table).Batch
+	if batch == nil {
+		return p.Batch
+	}
+
+	var options = *batch
+	if options.MaxSize == 0 {
+		options.MaxSize = p.Batch.MaxSize
+	}
+	return options
+}
+
+func (g Group) merge(defaults Group) Group {
+	if g.Name == "" {
+		g.Name = defaults.Name
+	}
+	if g.ProtoPackage == "" {
+		g.ProtoPackage = defaults.ProtoPackage
+	}
+	if g.GoPackage == "" {
+		g.GoPackage = defaults.GoPackage
+	}
+	if g.StoragePackage == "" {
+		g.StoragePackage = defaults.StoragePackage
+	}
+	if g.ServicePackage == "" {
+		g.ServicePackage = defaults.ServicePackage
+	}
+	if g.HandlerPackage == "" {
+		g.HandlerPackage = defaults.HandlerPackage
+	}
+	return g
+}
+
+// GoPackageName is the identifier the generated proto package is used as in Go code.
+func (g Group) GoPackageName() string {
+	return path.Base(g.GoPackage)
+}
+
+// ServiceAccessor is the gateway service manager method of the group, e.g. StorehouseClientService.
+func (g Group) ServiceAccessor() string {
+	return pascal(g.ProtoPackage)
+}
+
+// HandlerField is the gateway router field holding the group handler, e.g. HandlerClient.
+func (g Group) HandlerField() string {
+	return "Handler" + pascal(g.Name)
+}
+
+func pascal(s string) string {
+	var parts = strings.Split(s, "_")
+	for i, part := range parts {
+		if part != "" {
+			parts[i] = strings.ToUpper(part[:1]) + part[1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
@@ -0,0 +1,70 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadProjectBatch(t *testing.T) {
+	var tests = []struct {
+		name    string
+		json    string
+		want    Batch
+		wantErr string
+	}{
+		{
+			name: "default size",
+			json: `{}`,
+			want: Batch{MaxSize: DefaultBatchSize},
+		},
+		{
+			name: "project size",
+			json: `{"batch": {"max_size": 10, "partial": true}}`,
+			want: Batch{MaxSize: 10, Partial: true},
+		},
+		{
+			name: "table keeps the project size",
+			json: `{"batch": {"max_size": 10}, "tables": {"t": {"batch": {"partial": true}}}}`,
+			want: Batch{MaxSize: 10, Partial: true},
+		},
+		{
+			name: "table size",
+			json: `{"batch": {"max_size": 10}, "tables": {"t": {"batch": {"max_size": 5}}}}`,
+			want: Batch{MaxSize: 5},
+		},
+		{
+			name:    "project size not positive",
+			json:    `{"batch": {"max_size": 0}}`,
+			wantErr: "generate.json: batch max_size must be positive",
+		},
+		{
+			name:    "negative table size",
+			json:    `{"tables": {"t": {"batch": {"max_size": -1}}}}`,
+			wantErr: "generate.json: table t: batch max_size must not be negative",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var filename = filepath.Join(t.TempDir(), "generate.json")
+			if err := os.WriteFile(filename, []byte(test.json), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			project, err := LoadProject(filename)
+			if test.wantErr != "" {
+				if err == nil || err.Error() != filepath.Dir(filename)+"/"+test.wantErr {
+					t.Fatalf("got error %v, want %q", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := project.TableBatch("t"); got != test.want {
+				t.Errorf("TableBatch = %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
@@ -2,75 +2,78 @@ package handlers
 
 import (
 	"log"
-	"strings"
+	"path/filepath"
 
+	"githubc.com/asadbekGo/generate-code/config"
 	"githubc.com/asadbekGo/generate-code/pkg/helper"
+	"githubc.com/asadbekGo/generate-code/pkg/writer"
+	"githubc.com/asadbekGo/generate-code/schema"
 )
 
 var apiTexts string
 
-func MakeHandlerss(sqlBody []byte) error {
+func MakeHandlerss(cfg config.GenerateConfig, w *writer.Writer, s *schema.Schema, table *schema.Table) error {
 
-	var sqlTable = helper.RemoveEmptyRows(string(sqlBody))
-	if len(sqlTable) <= 0 {
-		return nil
-	}
+	var (
+		data = helper.NewTemplateData(cfg.Project, s, table)
+		name = "template.txt"
+	)
 
-	tableName, fields, err := helper.ParseSQLQuery(sqlTable)
+	sides, err := data.Join()
 	if err != nil {
-		log.Println("Error while ParseSQLQuery:", err.Error())
+		log.Println("Error while Join:", err.Error())
 		return err
 	}
+	if sides != nil {
+		name = "join.txt"
+	}
 
-	for index, field := range fields {
-		var fieldType = strings.Split(field, ":")
-		fields[index] = fieldType[0] + ":" + helper.SQLToGoType(fieldType[1])
+	templateHandler, err := helper.RenderTemplate(filepath.Join(cfg.TemplateDir, "handlers", name), data)
+	if err != nil {
+		log.Println("Error while RenderTemplate:", err.Error())
+		return err
 	}
 
-	var templateProtoFilename = "./handlers/template.txt"
-	templateProtoBody, err := helper.ReadFile(templateProtoFilename)
+	err = w.WriteFile(filepath.Join("handlers", data.Group.HandlerPackage, table.Name+".go"), templateHandler)
 	if err != nil {
-		log.Println("Error while ReadFile:", err.Error())
+		log.Println("Error while WriteFile:", err.Error())
 		return err
 	}
 
+	return nil
+}
+
+// AddRoutes adds the routes of the table to api.go. Every table of the
+// schema is added, not only the ones selected with -tables.
+func AddRoutes(cfg config.GenerateConfig, s *schema.Schema, table *schema.Table) error {
+
 	var (
-		templateProto      = string(templateProtoBody)
-		camelCaseText      = helper.SnakeToCamel(tableName)
-		upperHeadTableName = strings.ToUpper(string(camelCaseText[0])) + camelCaseText[1:]
-		tableNameTire      = strings.ReplaceAll(tableName, "_", "-")
+		data = helper.NewTemplateData(cfg.Project, s, table)
+		api  = "api.txt"
 	)
 
-	templateProto = strings.ReplaceAll(templateProto, "Template", upperHeadTableName)
-	templateProto = strings.ReplaceAll(templateProto, "/template", "/"+tableNameTire)
-	templateProto = strings.ReplaceAll(templateProto, "template_id", tableName+"_id")
-	templateProto = strings.ReplaceAll(templateProto, "template", camelCaseText)
-
-	err = helper.WriteFile("./generates/handlers/"+tableName+".go", templateProto)
+	sides, err := data.Join()
 	if err != nil {
-		log.Println("Error while WriteFile:", err.Error())
+		log.Println("Error while Join:", err.Error())
 		return err
 	}
+	if sides != nil {
+		api = "join_api.txt"
+	}
 
-	var apiFilename = "./handlers/api.txt"
-	apiBody, err := helper.ReadFile(apiFilename)
+	routes, err := helper.RenderTemplate(filepath.Join(cfg.TemplateDir, "handlers", api), data)
 	if err != nil {
-		log.Println("Error while ReadFile:", err.Error())
+		log.Println("Error while RenderTemplate:", err.Error())
 		return err
 	}
-	var api = string(apiBody)
-
-	api = strings.ReplaceAll(api, "Template", upperHeadTableName)
-	api = strings.ReplaceAll(api, "/template", "/"+tableNameTire)
-	api = strings.ReplaceAll(api, "template_id", tableName+"_id")
-	apiTexts += api + "\n"
+	apiTexts += routes + "\n"
 
 	return nil
 }
 
-func MakeApi() error {
+func MakeApi(w *writer.Writer) error {
 
-	err := helper.WriteFile("./generates/handlers/api.go", apiTexts)
+	err := w.WriteFile(filepath.Join("handlers", "api.go"), apiTexts)
 	if err != nil {
 		log.Println("Error while WriteFile:", err.Error())
 		return err
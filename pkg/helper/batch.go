@@ -0,0 +1,80 @@
+package helper
+
+import (
+	"strings"
+
+	"githubc.com/asadbekGo/generate-code/config"
+	"githubc.com/asadbekGo/generate-code/schema"
+)
+
+// maxQueryParams is the number of parameters a Postgres query may bind.
+const maxQueryParams = 65535
+
+// Batch returns the batch options of the table.
+func (d TemplateData) Batch() config.Batch {
+	return d.Project.TableBatch(d.Table.Name)
+}
+
+// BatchKey returns the key column the batch RPCs take lists of, nil when
+// the key has several columns or is not a string or integer. Tables
+// without one get no batch RPCs.
+func (d TemplateData) BatchKey() *schema.Column {
+	var key = d.Table.KeyColumns()
+	if len(key) != 1 || key[0].Array || d.EnumOf(key[0]) != nil {
+		return nil
+	}
+	if d.GoType(key[0]) != "string" && !d.IsInteger(key[0]) {
+		return nil
+	}
+	return key[0]
+}
+
+// BatchParam is the ids query parameter of the GetByIDs route.
+func (d TemplateData) BatchParam() Param {
+	return d.newParam("ids", "ids", d.BatchKey())
+}
+
+// CreateManyColumns are the columns the CreateMany INSERT writes, the key
+// columns set to DEFAULT when the Create INSERT writes none.
+func (d TemplateData) CreateManyColumns() []string {
+	if columns := d.InsertColumns(); len(columns) > 0 {
+		return columns
+	}
+
+	var columns []string
+	for _, column := range d.Table.KeyColumns() {
+		columns = append(columns, column.Name)
+	}
+	return columns
+}
+
+// CreateManyRow is a VALUES row of the CreateMany INSERT, a format its
+// parameter numbers are written into, e.g. "($%d, COALESCE($%d::integer, 0))".
+func (d TemplateData) CreateManyRow() string {
+	if len(d.InsertColumns()) == 0 {
+		return "(" + strings.TrimSuffix(strings.Repeat("DEFAULT, ", len(d.Table.KeyColumns())), ", ") + ")"
+	}
+
+	// a % of a default is escaped before the parameters are marked
+	var row = strings.ReplaceAll(d.insertValues(func() string { return "\x00" }), "%", "%%")
+	return "(" + strings.ReplaceAll(row, "\x00", "$%d") + ")"
+}
+
+// CreateManyParams is the number of parameters of a CreateMany row.
+func (d TemplateData) CreateManyParams() int {
+	var params = len(d.CreateFields())
+	if d.NewUUIDKey() != nil {
+		params++
+	}
+	return params
+}
+
+// CreateManySize is the number of items a CreateMany INSERT holds, at most
+// the batch size and as many as the parameters of a query allow.
+func (d TemplateData) CreateManySize() int {
+	var size = d.Batch().MaxSize
+	if params := d.CreateManyParams(); params > 0 && size > maxQueryParams/params {
+		size = maxQueryParams / params
+	}
+	return size
+}
@@ -0,0 +1,142 @@
+package helper
+
+import (
+	"strings"
+	"testing"
+
+	"githubc.com/asadbekGo/generate-code/config"
+)
+
+func TestBatchKey(t *testing.T) {
+	var tests = []struct {
+		name  string
+		table string
+		want  string
+	}{
+		{"uuid", "id UUID PRIMARY KEY", "id"},
+		{"serial", "id SERIAL PRIMARY KEY", "id"},
+		{"text", "code TEXT PRIMARY KEY", "code"},
+		{"composite", "a INT, b INT, PRIMARY KEY (a, b)", ""},
+		{"enum", "status status PRIMARY KEY", ""},
+		{"array", "tags TEXT[] PRIMARY KEY", ""},
+		{"float", "x DOUBLE PRECISION PRIMARY KEY", ""},
+		{"no key", "name TEXT", ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var data = parseTable(t, config.DefaultProject(), "CREATE TYPE status AS ENUM ('new'); CREATE TABLE t ("+test.table+");")
+
+			var got string
+			if key := data.BatchKey(); key != nil {
+				got = key.Name
+			}
+			if got != test.want {
+				t.Errorf("BatchKey = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestCreateMany(t *testing.T) {
+	var tests = []struct {
+		name    string
+		table   string
+		maxSize int
+		columns string
+		row     string
+		params  int
+		size    int
+	}{
+		{
+			name:    "generated uuid and defaults",
+			table:   "id UUID PRIMARY KEY, name TEXT NOT NULL, rate TEXT DEFAULT '10%', updated_at TIMESTAMP",
+			maxSize: 1000,
+			columns: "id, name, rate, updated_at",
+			row:     "($%d, $%d, COALESCE($%d::text, '10%%'), now())",
+			params:  3,
+			size:    1000,
+		},
+		{
+			name:    "key generated by the database",
+			table:   "id SERIAL PRIMARY KEY",
+			maxSize: 1000,
+			columns: "id",
+			row:     "(DEFAULT)",
+			size:    1000,
+		},
+		{
+			name:    "parameters limit the size",
+			table:   "id SERIAL PRIMARY KEY, a INT, b INT, c INT",
+			maxSize: 30000,
+			columns: "a, b, c",
+			row:     "($%d, $%d, $%d)",
+			params:  3,
+			size:    21845,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var project = config.DefaultProject()
+			project.Batch.MaxSize = test.maxSize
+
+			var data = parseTable(t, project, "CREATE TABLE t ("+test.table+");")
+			if got := strings.Join(data.CreateManyColumns(), ", "); got != test.columns {
+				t.Errorf("CreateManyColumns = %q, want %q", got, test.columns)
+			}
+			if got := data.CreateManyRow(); got != test.row {
+				t.Errorf("CreateManyRow = %q, want %q", got, test.row)
+			}
+			if got := data.CreateManyParams(); got != test.params {
+				t.Errorf("CreateManyParams = %d, want %d", got, test.params)
+			}
+			if got := data.CreateManySize(); got != test.size {
+				t.Errorf("CreateManySize = %d, want %d", got, test.size)
+			}
+		})
+	}
+}
+
+func TestBatchTemplates(t *testing.T) {
+	const table = "CREATE TABLE t (id UUID PRIMARY KEY, name TEXT NOT NULL);"
+
+	var project = config.DefaultProject()
+	project.Batch.MaxSize = 50
+
+	var data = parseTable(t, project, table)
+	containsAll(t, "storage/template_storage.txt", render(t, data, "storage/template_storage.txt"),
+		"const createManyTSize = 50",
+		"createManyTRow    = `($%d, $%d)`",
+		"valuesRows = append(valuesRows, fmt.Sprintf(createManyTRow, numbers...))",
+		"VALUES `+strings.Join(valuesRows, \", \")+`\n\t\tRETURNING id`, args...)",
+		"if len(resp) != len(ids) {\n\t\treturn resp, pgx.ErrNoRows",
+	)
+	containsAll(t, "storage/template_service.txt", render(t, data, "storage/template_service.txt"),
+		"const tBatchSize = 50",
+		"if size > tBatchSize {",
+		"Description: \"must hold at most 50 items\",",
+		"requested[id] = true",
+		"len(requested)-len(ids))",
+	)
+
+	var proto = render(t, data, "protos/template.proto")
+	if strings.Contains(proto, "errors") || strings.Contains(proto, "TBatchError") {
+		t.Errorf("protos/template.proto: an atomic batch declares the errors field\n%s", proto)
+	}
+
+	project.Batch.Partial = true
+	data = parseTable(t, project, table)
+	containsAll(t, "protos/template.proto", render(t, data, "protos/template.proto"),
+		"    repeated T ts = 1;\n    // errors of the items that were not created\n    repeated TBatchError errors = 2;\n}",
+		"message TBatchError {",
+	)
+	containsAll(t, "storage/template_service.txt", render(t, data, "storage/template_service.txt"),
+		"resp.Errors = append(resp.Errors, batchErrorT(n, err))",
+	)
+
+	data = parseTable(t, config.DefaultProject(), "CREATE TABLE t (id SERIAL PRIMARY KEY);")
+	containsAll(t, "storage/template_storage.txt", render(t, data, "storage/template_storage.txt"),
+		"for range items[start:end] {\n\t\t\tvaluesRows = append(valuesRows, createManyTRow)\n\t\t}",
+	)
+}
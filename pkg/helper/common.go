@@ -0,0 +1,20 @@
+package helper
+
+// ListField is the expression reading a pagination, filter or patch field
+// of req. With Project.CommonProto the fields live in the messages of
+// common.proto, e.g. req.GetPagination().GetLimit().
+func (d TemplateData) ListField(field string) string {
+	var getter = "Get" + SnakeToPascal(field) + "()"
+	if !d.Project.CommonProto {
+		return "req." + getter
+	}
+
+	switch field {
+	case "limit", "page":
+		return "req.GetPagination()." + getter
+	case "fields":
+		return "req.GetPatch()." + getter
+	default:
+		return "req.GetFilter()." + getter
+	}
+}
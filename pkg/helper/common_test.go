@@ -0,0 +1,77 @@
+package helper
+
+import (
+	"strings"
+	"testing"
+
+	"githubc.com/asadbekGo/generate-code/config"
+)
+
+func TestListField(t *testing.T) {
+	var tests = []struct {
+		field  string
+		want   string
+		common string
+	}{
+		{"limit", "req.GetLimit()", "req.GetPagination().GetLimit()"},
+		{"page", "req.GetPage()", "req.GetPagination().GetPage()"},
+		{"search", "req.GetSearch()", "req.GetFilter().GetSearch()"},
+		{"where_query", "req.GetWhereQuery()", "req.GetFilter().GetWhereQuery()"},
+		{"filters", "req.GetFilters()", "req.GetFilter().GetFilters()"},
+		{"fields", "req.GetFields()", "req.GetPatch().GetFields()"},
+	}
+
+	for _, test := range tests {
+		var data = TemplateData{}
+		if got := data.ListField(test.field); got != test.want {
+			t.Errorf("ListField(%q) = %q, want %q", test.field, got, test.want)
+		}
+
+		data.Project.CommonProto = true
+		if got := data.ListField(test.field); got != test.common {
+			t.Errorf("ListField(%q) with common.proto = %q, want %q", test.field, got, test.common)
+		}
+	}
+}
+
+func TestCommonProtoTemplates(t *testing.T) {
+	var project = config.DefaultProject()
+	project.CommonProto = true
+
+	var data = parseTable(t, project, "CREATE TABLE client (id UUID PRIMARY KEY); CREATE TABLE t (id UUID PRIMARY KEY, client_id UUID REFERENCES client(id), name TEXT);")
+
+	containsAll(t, "protos/common.proto", render(t, data, "protos/common.proto"),
+		"package storehouse_client_service;",
+		"message Pagination {",
+		"message ListFilter {",
+		"message PatchFields {",
+	)
+
+	var proto = render(t, data, "protos/template.proto")
+	containsAll(t, "protos/template.proto", proto,
+		`import "common.proto";`,
+		"message GetListTRequest {\n    Pagination pagination = 1;\n    ListFilter filter = 2;\n    optional string client_id = 3;\n}",
+		"PatchFields patch = 2;",
+	)
+	if containsAny(proto, "int32 limit", "google.protobuf.Struct filters") {
+		t.Errorf("protos/template.proto declares the common fields itself:\n%s", proto)
+	}
+
+	containsAll(t, "storage/template_storage.txt", render(t, data, "storage/template_storage.txt"),
+		"if req.GetPagination().GetLimit() > 0 {",
+		"req.GetFilter().GetFilters().AsMap()",
+		"fields = req.GetPatch().GetFields().AsMap()",
+	)
+	containsAll(t, "handlers/template.txt", render(t, data, "handlers/template.txt"),
+		"Pagination: &storehouse_client_service.Pagination{Limit: int32(limit), Page: int32(page)},",
+	)
+}
+
+func containsAny(s string, substrs ...string) bool {
+	for _, substr := range substrs {
+		if strings.Contains(s, substr) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,141 @@
+package helper
+
+import (
+	"fmt"
+	"strings"
+
+	"githubc.com/asadbekGo/generate-code/schema"
+)
+
+// Expansion is a foreign key whose referenced row Get and GetList load on
+// request into a nested message, see config.TableConfig.Expand.
+type Expansion struct {
+	Parent
+	// Field is the nested field and the expand value requesting it, e.g.
+	// client for client_id and approver_ref for an approver column.
+	Field string
+	// Message is the nested message, e.g. ComingClient.
+	Message string
+	// RefColumn is the referenced column, Columns starts with it and is
+	// followed by the display columns.
+	RefColumn *schema.Column
+	Columns   []*schema.Column
+	// Ref renders the columns of the referenced table.
+	Ref TemplateData
+}
+
+// GetRequest is the message GetByID takes: the primary key, or
+// Get<Table>Request carrying the key and the expand list when the table has
+// expansions.
+func (d TemplateData) GetRequest() (string, error) {
+	expansions, err := d.Expansions()
+	if err != nil {
+		return "", err
+	}
+	if len(expansions) > 0 {
+		return "Get" + SnakeToPascal(d.Table.Name) + "Request", nil
+	}
+	return SnakeToPascal(d.Table.Name) + "PrimaryKey", nil
+}
+
+// Expansions returns the expandable foreign keys of the table in column
+// order.
+func (d TemplateData) Expansions() ([]Expansion, error) {
+	var (
+		expansions []Expansion
+		parents    = d.Parents()
+	)
+
+	for _, name := range d.Options.Expand {
+		var found bool
+		for _, parent := range parents {
+			found = found || parent.Column.Name == name
+		}
+		if !found {
+			return nil, fmt.Errorf("table %s: expand %s is not a single column foreign key", d.Table.Name, name)
+		}
+	}
+
+	for _, parent := range parents {
+		if !contains(d.Options.Expand, parent.Column.Name) {
+			continue
+		}
+
+		var refTable = d.Schema.Table(parent.Table)
+		if refTable == nil {
+			return nil, fmt.Errorf("table %s: expand %s: referenced table %s is not part of the schema", d.Table.Name, parent.Column.Name, parent.Table)
+		}
+
+		var expansion = Expansion{
+			Parent:  parent,
+			Field:   strings.TrimSuffix(parent.Column.Name, "_id"),
+			Message: SnakeToPascal(d.Table.Name) + parent.By,
+			Ref:     NewTemplateData(d.Project, d.Schema, refTable),
+		}
+
+		// a column not ending in _id, e.g. approver, already holds the name
+		if d.Table.HasColumn(expansion.Field) {
+			expansion.Field += "_ref"
+		}
+		if d.Table.HasColumn(expansion.Field) {
+			return nil, fmt.Errorf("table %s: expand %s: field %s is already a column", d.Table.Name, parent.Column.Name, expansion.Field)
+		}
+		for _, other := range expansions {
+			if other.Field == expansion.Field {
+				return nil, fmt.Errorf("table %s: expand %s and %s: both expand into the field %s", d.Table.Name, other.Column.Name, parent.Column.Name, expansion.Field)
+			}
+		}
+
+		expansion.RefColumn = refTable.Column(refColumnName(parent.Column.References, refTable))
+		if expansion.RefColumn == nil {
+			return nil, fmt.Errorf("table %s: expand %s: referenced column is not part of table %s", d.Table.Name, parent.Column.Name, refTable.Name)
+		}
+		expansion.Columns = append(expansion.Columns, expansion.RefColumn)
+
+		var display, ok = d.Project.Display[refTable.Name]
+		if !ok && refTable.HasColumn("name") {
+			display = []string{"name"}
+		}
+
+		for _, name := range display {
+			var column = refTable.Column(name)
+			if column == nil {
+				return nil, fmt.Errorf("display column %s.%s does not exist", refTable.Name, name)
+			}
+			if !expansion.Ref.scalar(column) {
+				return nil, fmt.Errorf("display column %s.%s: only scalar columns can be displayed", refTable.Name, name)
+			}
+			if column != expansion.RefColumn {
+				expansion.Columns = append(expansion.Columns, column)
+			}
+		}
+
+		expansions = append(expansions, expansion)
+	}
+
+	return expansions, nil
+}
+
+// ExpandFields lists the expand values of the table for documentation,
+// e.g. "client, product".
+func (d TemplateData) ExpandFields() (string, error) {
+	expansions, err := d.Expansions()
+	if err != nil {
+		return "", err
+	}
+
+	var fields []string
+	for _, expansion := range expansions {
+		fields = append(fields, expansion.Field)
+	}
+	return strings.Join(fields, ", "), nil
+}
+
+func contains(s []string, e string) bool {
+	for _, a := range s {
+		if a == e {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,96 @@
+package helper
+
+import (
+	"reflect"
+	"testing"
+
+	"githubc.com/asadbekGo/generate-code/config"
+)
+
+func TestExpansions(t *testing.T) {
+	const parents = `
+CREATE TABLE client (id UUID PRIMARY KEY, name TEXT);
+CREATE TABLE "user" (id SERIAL PRIMARY KEY, name TEXT);
+`
+
+	var tests = []struct {
+		name    string
+		table   string
+		expand  []string
+		want    []string
+		wantErr string
+	}{
+		{
+			name:   "column ending in _id",
+			table:  "id UUID PRIMARY KEY, client_id UUID REFERENCES client(id)",
+			expand: []string{"client_id"},
+			want:   []string{"client TClient"},
+		},
+		{
+			name:   "column without _id",
+			table:  "id UUID PRIMARY KEY, approver INT REFERENCES \"user\"(id), client_id UUID REFERENCES client(id)",
+			expand: []string{"approver", "client_id"},
+			want:   []string{"approver_ref TApprover", "client TClient"},
+		},
+		{
+			name:   "column named like the field",
+			table:  "id UUID PRIMARY KEY, client TEXT, client_id UUID REFERENCES client(id)",
+			expand: []string{"client_id"},
+			want:   []string{"client_ref TClient"},
+		},
+		{
+			name:    "field is a column",
+			table:   "id UUID PRIMARY KEY, approver INT REFERENCES \"user\"(id), approver_ref TEXT",
+			expand:  []string{"approver"},
+			wantErr: "table t: expand approver: field approver_ref is already a column",
+		},
+		{
+			name:    "same field",
+			table:   "id UUID PRIMARY KEY, client UUID REFERENCES client(id), client_id UUID REFERENCES client(id)",
+			expand:  []string{"client", "client_id"},
+			wantErr: "table t: expand client and client_id: both expand into the field client_ref",
+		},
+		{
+			name:    "not a foreign key",
+			table:   "id UUID PRIMARY KEY, client_id UUID",
+			expand:  []string{"client_id"},
+			wantErr: "table t: expand client_id is not a single column foreign key",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var project = config.DefaultProject()
+			project.Tables = map[string]config.TableConfig{"t": {Expand: test.expand}}
+
+			var data = parseTable(t, project, parents+"CREATE TABLE t ("+test.table+");")
+
+			expansions, err := data.Expansions()
+			if test.wantErr != "" {
+				if err == nil || err.Error() != test.wantErr {
+					t.Fatalf("got error %v, want %q", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []string
+			for _, expansion := range expansions {
+				got = append(got, expansion.Field+" "+expansion.Message)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+
+			if len(test.expand) > 1 {
+				return
+			}
+			containsAll(t, "protos/template.proto", render(t, data, "protos/template.proto"),
+				expansions[0].Message+" "+expansions[0].Field+" = ",
+			)
+			render(t, data, "storage/template_storage.txt")
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package helper
+
+import (
+	"fmt"
+
+	"githubc.com/asadbekGo/generate-code/schema"
+)
+
+// JoinSide is one of the two foreign keys of a many-to-many join table and
+// Other the foreign key of the opposite side.
+type JoinSide struct {
+	Parent
+	Other Parent
+}
+
+// Join returns both sides of a join table, or nil for any other table.
+//
+// A table is detected as a join table when it has exactly two NOT NULL
+// single column foreign keys that are its primary key or a unique
+// constraint, and no other columns than the created_at/updated_at
+// timestamps. Setting join in the table config also accepts a surrogate key
+// the database or Create fills and other columns the database can fill.
+func (d TemplateData) Join() ([]JoinSide, error) {
+	if d.Options.Join != nil && !*d.Options.Join {
+		return nil, nil
+	}
+	var declared = d.Options.Join != nil
+
+	var parents = d.Parents()
+	if len(parents) != 2 {
+		if declared {
+			return nil, fmt.Errorf("table %s: a join table needs exactly two single column foreign keys, found %d", d.Table.Name, len(parents))
+		}
+		return nil, nil
+	}
+
+	var pair = []*schema.Column{parents[0].Column, parents[1].Column}
+	if !declared && (!pair[0].NotNull || !pair[1].NotNull || !uniquePair(d.Table, pair)) {
+		return nil, nil
+	}
+
+	var newUUIDKey = d.NewUUIDKey()
+	for _, column := range d.Table.Columns {
+		switch {
+		case column == parents[0].Column || column == parents[1].Column:
+		case column.Name == "created_at" || column.Name == "updated_at":
+		case column == newUUIDKey || generatedByDB(column):
+		case column.PrimaryKey:
+			return d.notJoin(declared, "key column %s is filled by neither the database nor Create", column.Name)
+		case !declared:
+			return nil, nil
+		case column.NotNull:
+			return d.notJoin(declared, "column %s needs a value", column.Name)
+		}
+	}
+
+	return []JoinSide{
+		{Parent: parents[0], Other: parents[1]},
+		{Parent: parents[1], Other: parents[0]},
+	}, nil
+}
+
+// uniquePair reports whether the pair is the primary key, or a unique
+// constraint of a table without a primary key.
+func uniquePair(table *schema.Table, pair []*schema.Column) bool {
+	if len(table.PrimaryKey) > 0 {
+		return sameColumns(table.PrimaryKey, pair)
+	}
+	for _, index := range table.Indexes {
+		if index.Unique && !index.Expression && index.Predicate == "" && sameColumns(index.Columns, pair) {
+			return true
+		}
+	}
+	return false
+}
+
+func (d TemplateData) notJoin(declared bool, format string, args ...interface{}) ([]JoinSide, error) {
+	if !declared {
+		return nil, nil
+	}
+	return nil, fmt.Errorf("table %s: not a join table, %s", d.Table.Name, fmt.Sprintf(format, args...))
+}
+
+// Cast is the SQL type a query parameter of the column is cast to.
+func (d TemplateData) Cast(column *schema.Column) string {
+	return castType(column)
+}
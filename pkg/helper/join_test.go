@@ -0,0 +1,102 @@
+package helper
+
+import (
+	"testing"
+
+	"githubc.com/asadbekGo/generate-code/config"
+	"githubc.com/asadbekGo/generate-code/pkg/parser"
+)
+
+func TestJoin(t *testing.T) {
+	const parents = `
+CREATE TABLE chapter (id UUID PRIMARY KEY);
+CREATE TABLE "user" (id SERIAL PRIMARY KEY);
+`
+
+	var (
+		yes = true
+		no  = false
+	)
+
+	var tests = []struct {
+		name    string
+		table   string
+		indexes string
+		join    *bool
+		want    bool
+		wantErr bool
+	}{
+		{
+			name:  "pair is the primary key",
+			table: "chapter_id UUID REFERENCES chapter(id), user_id INT REFERENCES \"user\"(id), created_at TIMESTAMP DEFAULT now(), PRIMARY KEY (chapter_id, user_id)",
+			want:  true,
+		},
+		{
+			name:  "unique pair without a key",
+			table: "chapter_id UUID NOT NULL REFERENCES chapter(id), user_id INT NOT NULL REFERENCES \"user\"(id), UNIQUE (chapter_id, user_id)",
+			want:  true,
+		},
+		{
+			name:  "nullable side",
+			table: "chapter_id UUID REFERENCES chapter(id), user_id INT NOT NULL REFERENCES \"user\"(id), UNIQUE (chapter_id, user_id)",
+		},
+		{
+			name:  "pair is not unique",
+			table: "chapter_id UUID NOT NULL REFERENCES chapter(id), user_id INT NOT NULL REFERENCES \"user\"(id)",
+		},
+		{
+			name:    "partial unique pair",
+			table:   "chapter_id UUID NOT NULL REFERENCES chapter(id), user_id INT NOT NULL REFERENCES \"user\"(id)",
+			indexes: "CREATE UNIQUE INDEX ON t (chapter_id, user_id) WHERE chapter_id IS NOT NULL;",
+		},
+		{
+			name:  "surrogate key",
+			table: "id UUID PRIMARY KEY DEFAULT gen_random_uuid(), chapter_id UUID NOT NULL REFERENCES chapter(id), user_id INT NOT NULL REFERENCES \"user\"(id), UNIQUE (chapter_id, user_id)",
+		},
+		{
+			name:  "declared with a surrogate key",
+			table: "id UUID PRIMARY KEY, chapter_id UUID REFERENCES chapter(id), user_id INT NOT NULL REFERENCES \"user\"(id)",
+			join:  &yes,
+			want:  true,
+		},
+		{
+			name:  "declared off",
+			table: "chapter_id UUID REFERENCES chapter(id), user_id INT REFERENCES \"user\"(id), PRIMARY KEY (chapter_id, user_id)",
+			join:  &no,
+		},
+		{
+			name:  "other column",
+			table: "chapter_id UUID REFERENCES chapter(id), user_id INT REFERENCES \"user\"(id), role TEXT, PRIMARY KEY (chapter_id, user_id)",
+		},
+		{
+			name:    "declared with a required column",
+			table:   "chapter_id UUID REFERENCES chapter(id), user_id INT REFERENCES \"user\"(id), role TEXT NOT NULL, PRIMARY KEY (chapter_id, user_id)",
+			join:    &yes,
+			wantErr: true,
+		},
+		{
+			name:    "declared with one foreign key",
+			table:   "chapter_id UUID REFERENCES chapter(id), user_id INT, PRIMARY KEY (chapter_id, user_id)",
+			join:    &yes,
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s, err := parser.Parse("test.sql", parents+"CREATE TABLE t ("+test.table+");"+test.indexes)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var project = config.Project{Tables: map[string]config.TableConfig{"t": {Join: test.join}}}
+			sides, err := NewTemplateData(project, s, s.Table("t")).Join()
+			if (err != nil) != test.wantErr {
+				t.Fatalf("got error %v, want an error: %v", err, test.wantErr)
+			}
+			if (sides != nil) != test.want {
+				t.Errorf("got join %v, want %v", sides != nil, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,261 @@
+package helper
+
+import (
+	"fmt"
+	"strings"
+
+	"githubc.com/asadbekGo/generate-code/schema"
+)
+
+// NewUUIDKey returns the key column Create fills with uuid.New(), that is a
+// single uuid key the database has no default for.
+func (d TemplateData) NewUUIDKey() *schema.Column {
+	var key = d.Table.KeyColumns()
+	if len(key) == 1 && key[0].Type == "uuid" && !key[0].Array && !generatedByDB(key[0]) {
+		return key[0]
+	}
+	return nil
+}
+
+// CreateFields returns the columns set from the Create request, the key
+// columns neither Create nor the database generate followed by the
+// writable fields.
+func (d TemplateData) CreateFields() []*schema.Column {
+	var fields []*schema.Column
+	if d.NewUUIDKey() == nil {
+		for _, column := range d.Table.KeyColumns() {
+			if !generatedByDB(column) {
+				fields = append(fields, column)
+			}
+		}
+	}
+	return append(fields, d.Table.WritableFields()...)
+}
+
+// InsertColumns are the columns the Create INSERT writes, updated_at last
+// when the table has it.
+func (d TemplateData) InsertColumns() []string {
+	var columns []string
+	if key := d.NewUUIDKey(); key != nil {
+		columns = append(columns, key.Name)
+	}
+	for _, column := range d.CreateFields() {
+		columns = append(columns, column.Name)
+	}
+	if d.Table.HasColumn("updated_at") {
+		columns = append(columns, "updated_at")
+	}
+	return columns
+}
+
+// InsertValues is the VALUES list of the Create INSERT, NewUUIDKey is $1.
+func (d TemplateData) InsertValues() string {
+	var n int
+	return d.insertValues(func() string {
+		n++
+		return fmt.Sprintf("$%d", n)
+	})
+}
+
+// insertValues returns the VALUES list with the parameters param returns
+// in order.
+func (d TemplateData) insertValues(param func() string) string {
+	var values []string
+	if d.NewUUIDKey() != nil {
+		values = append(values, param())
+	}
+
+	for _, column := range d.CreateFields() {
+		values = append(values, d.InsertValue(column, param()))
+	}
+
+	if d.Table.HasColumn("updated_at") {
+		values = append(values, "now()")
+	}
+
+	return strings.Join(values, ", ")
+}
+
+// UpdateSet are the assignments of the Update statement. A table with
+// neither writable fields nor updated_at sets its key to itself, so that
+// Update still reports whether the row exists.
+func (d TemplateData) UpdateSet() []string {
+	var set []string
+	for _, column := range d.Table.WritableFields() {
+		set = append(set, column.Name+" = :"+column.Name)
+	}
+	if d.Table.HasColumn("updated_at") {
+		set = append(set, "updated_at = now()")
+	}
+	if len(set) == 0 {
+		for _, column := range d.Table.KeyColumns() {
+			set = append(set, column.Name+" = "+column.Name)
+		}
+	}
+	return set
+}
+
+// SelectColumns is the SELECT list of a row, the read columns followed by
+// the created_at and updated_at timestamps the table has.
+func (d TemplateData) SelectColumns() []string {
+	var columns []string
+	for _, column := range d.Table.ReadColumns() {
+		columns = append(columns, d.SelectColumn(column))
+	}
+	for _, name := range []string{"created_at", "updated_at"} {
+		if d.Table.HasColumn(name) {
+			columns = append(columns, "TO_CHAR("+name+", 'YYYY-MM-DD HH24:MI:SS')")
+		}
+	}
+	return columns
+}
+
+// ListOrder is the ORDER BY of GetAll, the newest rows first when the
+// table has created_at and the key order otherwise.
+func (d TemplateData) ListOrder() string {
+	if d.Table.HasColumn("created_at") {
+		return "created_at DESC"
+	}
+
+	var columns []string
+	for _, column := range d.Table.KeyColumns() {
+		columns = append(columns, column.Name)
+	}
+	return strings.Join(columns, ", ")
+}
+
+// KeyList is the comma separated list of the key columns as selected.
+func (d TemplateData) KeyList() string {
+	var columns []string
+	for _, column := range d.Table.KeyColumns() {
+		columns = append(columns, d.SelectColumn(column))
+	}
+	return strings.Join(columns, ", ")
+}
+
+// KeyCondition is the WHERE condition matching the key bound to $start
+// and the following parameters.
+func (d TemplateData) KeyCondition(start int) string {
+	var conditions []string
+	for i, column := range d.Table.KeyColumns() {
+		conditions = append(conditions, fmt.Sprintf("%s = $%d", column.Name, start+i))
+	}
+	return strings.Join(conditions, " AND ")
+}
+
+// KeyNamedCondition is the WHERE condition matching the key bound to
+// :name parameters.
+func (d TemplateData) KeyNamedCondition() string {
+	var conditions []string
+	for _, column := range d.Table.KeyColumns() {
+		conditions = append(conditions, column.Name+" = :"+column.Name)
+	}
+	return strings.Join(conditions, " AND ")
+}
+
+// KeyFields are the fields of a PrimaryKey message literal read from the
+// getters of receiver, e.g. "Id: req.GetId()".
+func (d TemplateData) KeyFields(receiver string) string {
+	var fields []string
+	for _, column := range d.Table.KeyColumns() {
+		fields = append(fields, fmt.Sprintf("%s: %s.Get%s()", SnakeToPascal(column.Name), receiver, SnakeToPascal(column.Name)))
+	}
+	return strings.Join(fields, ", ")
+}
+
+// RouteParam is the gateway path parameter of a key column, an id column
+// is named after the table, e.g. coming_id.
+func (d TemplateData) RouteParam(column *schema.Column) string {
+	if column.Name == "id" {
+		return d.Table.Name + "_id"
+	}
+	return column.Name
+}
+
+// RoutePath is the gin path of a single row, e.g. /coming/:coming_id.
+func (d TemplateData) RoutePath() string {
+	var path = "/" + SnakeToKebab(d.Table.Name)
+	for _, param := range d.KeyParams() {
+		path += "/:" + param.Name
+	}
+	return path
+}
+
+// SwaggerPath is the swagger path of a single row, e.g. /coming/{coming_id}.
+func (d TemplateData) SwaggerPath() string {
+	var path = "/" + SnakeToKebab(d.Table.Name)
+	for _, param := range d.KeyParams() {
+		path += "/{" + param.Name + "}"
+	}
+	return path
+}
+
+// KeyParams returns the path parameters of the key columns.
+func (d TemplateData) KeyParams() []Param {
+	var params []Param
+	for _, column := range d.Table.KeyColumns() {
+		var label = column.Name
+		if column.Name == "id" {
+			label = SnakeToCamel(d.Table.Name) + " id"
+		}
+		params = append(params, d.newParam(d.RouteParam(column), label, column))
+	}
+	return params
+}
+
+// KeyFromParams are the fields of a PrimaryKey message literal read from
+// the parsed path parameters.
+func (d TemplateData) KeyFromParams() string {
+	var fields []string
+	for _, param := range d.KeyParams() {
+		fields = append(fields, SnakeToPascal(param.Column.Name)+": "+param.Value())
+	}
+	return strings.Join(fields, ", ")
+}
+
+// IsInteger reports whether the column is a signed integer in Go.
+func (d TemplateData) IsInteger(column *schema.Column) bool {
+	switch d.GoType(column) {
+	case "int32", "int64":
+		return true
+	}
+	return false
+}
+
+// HasIntegerParam reports whether a path parameter is parsed with strconv.
+func (d TemplateData) HasIntegerParam() bool {
+	for _, param := range d.handlerParams() {
+		if param.Integer {
+			return true
+		}
+	}
+	return false
+}
+
+// HasUUIDParam reports whether a path parameter is validated as uuid.
+func (d TemplateData) HasUUIDParam() bool {
+	for _, param := range d.handlerParams() {
+		if param.UUID {
+			return true
+		}
+	}
+	return false
+}
+
+func (d TemplateData) handlerParams() []Param {
+	var params []Param
+	if sides, _ := d.Join(); sides == nil {
+		params = d.KeyParams()
+	}
+	for _, parent := range d.Parents() {
+		params = append(params, parent.Param)
+	}
+	for _, lookup := range d.Lookups() {
+		params = append(params, lookup.Params...)
+	}
+	return params
+}
+
+func generatedByDB(column *schema.Column) bool {
+	return column.HasDefault || column.Identity || column.Generated
+}
@@ -0,0 +1,84 @@
+package helper
+
+import (
+	"testing"
+
+	"githubc.com/asadbekGo/generate-code/config"
+)
+
+func TestKeys(t *testing.T) {
+	var tests = []struct {
+		name           string
+		sql            string
+		condition      string
+		namedCondition string
+		fields         string
+		routePath      string
+		fromParams     string
+	}{
+		{
+			name:           "id key",
+			sql:            "CREATE TABLE t (id UUID PRIMARY KEY, name TEXT);",
+			condition:      "id = $1",
+			namedCondition: "id = :id",
+			fields:         "Id: req.GetId()",
+			routePath:      "/t/:t_id",
+			fromParams:     "Id: tId",
+		},
+		{
+			name:           "composite key",
+			sql:            "CREATE TABLE t (tenant_id UUID, number INT, name TEXT, PRIMARY KEY (tenant_id, number));",
+			condition:      "tenant_id = $1 AND number = $2",
+			namedCondition: "tenant_id = :tenant_id AND number = :number",
+			fields:         "TenantId: req.GetTenantId(), Number: req.GetNumber()",
+			routePath:      "/t/:tenant_id/:number",
+			fromParams:     "TenantId: tenantId, Number: int32(number)",
+		},
+		{
+			name:           "no primary key",
+			sql:            "CREATE TABLE t (id BIGINT NOT NULL, name TEXT);",
+			condition:      "id = $1",
+			namedCondition: "id = :id",
+			fields:         "Id: req.GetId()",
+			routePath:      "/t/:t_id",
+			fromParams:     "Id: tId",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var data = parseTable(t, config.DefaultProject(), test.sql)
+			if got := data.KeyCondition(1); got != test.condition {
+				t.Errorf("KeyCondition = %q, want %q", got, test.condition)
+			}
+			if got := data.KeyNamedCondition(); got != test.namedCondition {
+				t.Errorf("KeyNamedCondition = %q, want %q", got, test.namedCondition)
+			}
+			if got := data.KeyFields("req"); got != test.fields {
+				t.Errorf("KeyFields = %q, want %q", got, test.fields)
+			}
+			if got := data.RoutePath(); got != test.routePath {
+				t.Errorf("RoutePath = %q, want %q", got, test.routePath)
+			}
+			if got := data.KeyFromParams(); got != test.fromParams {
+				t.Errorf("KeyFromParams = %q, want %q", got, test.fromParams)
+			}
+		})
+	}
+}
+
+func TestCompositeKeyTemplates(t *testing.T) {
+	var data = parseTable(t, config.DefaultProject(), "CREATE TABLE t (tenant_id UUID, number INT, name TEXT, PRIMARY KEY (tenant_id, number));")
+
+	containsAll(t, "protos/template.proto", render(t, data, "protos/template.proto"),
+		"message TPrimaryKey {\n    string tenant_id = 1;\n    int32 number = 2;\n}",
+	)
+	containsAll(t, "storage/template_storage.txt", render(t, data, "storage/template_storage.txt"),
+		"WHERE tenant_id = $1 AND number = $2",
+		"req.GetTenantId(), req.GetNumber()",
+	)
+	containsAll(t, "handlers/template.txt", render(t, data, "handlers/template.txt"),
+		`number, err := strconv.ParseInt(c.Param("number"), 10, 32)`,
+		"TenantId: tenantId, Number: int32(number)",
+	)
+}
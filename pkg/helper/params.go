@@ -0,0 +1,59 @@
+package helper
+
+import (
+	"strings"
+
+	"githubc.com/asadbekGo/generate-code/schema"
+)
+
+// Param is a path parameter of a gateway route and the column it is
+// matched against.
+type Param struct {
+	Name    string
+	Var     string
+	Label   string
+	Column  *schema.Column
+	UUID    bool
+	Integer bool
+	Bits    int
+}
+
+func (d TemplateData) newParam(name, label string, column *schema.Column) Param {
+	var param = Param{
+		Name:    name,
+		Var:     GoVarName(name),
+		Label:   label,
+		Column:  column,
+		UUID:    column.Type == "uuid",
+		Integer: d.IsInteger(column),
+		Bits:    64,
+	}
+
+	if d.GoType(column) == "int32" {
+		param.Bits = 32
+	}
+
+	return param
+}
+
+// Description is the comment of the column on a single line, swagger
+// descriptions are quoted.
+func (p Param) Description() string {
+	return strings.ReplaceAll(strings.Join(Lines(p.Column.Comment), " "), `"`, "'")
+}
+
+// SwaggerType is the swagger type of the parameter.
+func (p Param) SwaggerType() string {
+	if p.Integer {
+		return "integer"
+	}
+	return "string"
+}
+
+// Value is the parsed parameter converted to the Go type of its column.
+func (p Param) Value() string {
+	if p.Integer && p.Bits == 32 {
+		return "int32(" + p.Var + ")"
+	}
+	return p.Var
+}
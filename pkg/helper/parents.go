@@ -0,0 +1,75 @@
+package helper
+
+import (
+	"strings"
+
+	"githubc.com/asadbekGo/generate-code/schema"
+)
+
+// Parent is a single column foreign key the rows of a table are listed by,
+// e.g. GetListComingByClient served on /client/:client_id/coming.
+type Parent struct {
+	Param
+	// By names the parent in RPC and handler names, e.g. Client.
+	By          string
+	Table       string
+	RoutePath   string
+	SwaggerPath string
+}
+
+// Parents returns the single column foreign keys of the table in column
+// order.
+func (d TemplateData) Parents() []Parent {
+	var parents []Parent
+	for _, column := range d.Table.Columns {
+		if column.References == nil || column.Array {
+			continue
+		}
+
+		var (
+			refTable = column.References.RefTable
+			by       = strings.TrimSuffix(column.Name, "_id")
+			name     = d.parentParam(column.References)
+			path     = "/" + SnakeToKebab(refTable) + "/%s/" + SnakeToKebab(d.Table.Name)
+		)
+
+		// A column not named after the referenced table gets its own path,
+		// a table may reference the same parent more than once.
+		if column.Name != refTable+"_id" {
+			path += "/" + SnakeToKebab(by)
+		}
+
+		parents = append(parents, Parent{
+			Param:       d.newParam(name, name, column),
+			By:          SnakeToPascal(by),
+			Table:       refTable,
+			RoutePath:   strings.Replace(path, "%s", ":"+name, 1),
+			SwaggerPath: strings.Replace(path, "%s", "{"+name+"}", 1),
+		})
+	}
+	return parents
+}
+
+// parentParam is the path parameter the referenced table names its own
+// key with, so the routes of both tables share it.
+func (d TemplateData) parentParam(foreignKey *schema.ForeignKey) string {
+	var refColumn = refColumnName(foreignKey, d.Schema.Table(foreignKey.RefTable))
+	if refColumn == "id" {
+		return foreignKey.RefTable + "_id"
+	}
+	return refColumn
+}
+
+// refColumnName is the column the foreign key points at, the primary key
+// of refTable when the REFERENCES clause names none.
+func refColumnName(foreignKey *schema.ForeignKey, refTable *schema.Table) string {
+	if len(foreignKey.RefColumns) == 1 {
+		return foreignKey.RefColumns[0]
+	}
+	if refTable != nil {
+		if key := refTable.KeyColumns(); len(key) == 1 {
+			return key[0].Name
+		}
+	}
+	return "id"
+}
@@ -0,0 +1,67 @@
+package helper
+
+import (
+	"reflect"
+	"testing"
+
+	"githubc.com/asadbekGo/generate-code/config"
+)
+
+const parentsTable = `
+CREATE TABLE client (id SERIAL PRIMARY KEY);
+CREATE TABLE warehouse (code TEXT PRIMARY KEY);
+CREATE TABLE t (
+	id UUID PRIMARY KEY,
+	client_id INT REFERENCES client(id),
+	owner_id UUID REFERENCES t(id),
+	warehouse TEXT REFERENCES warehouse,
+	tag_ids UUID[]
+);
+`
+
+func TestParents(t *testing.T) {
+	var data = parseTable(t, config.DefaultProject(), parentsTable)
+
+	var want = []string{
+		"Client client_id integer /client/:client_id/t",
+		"Owner t_id uuid /t/:t_id/t/owner",
+		"Warehouse code string /warehouse/:code/t/warehouse",
+	}
+
+	var got []string
+	for _, parent := range data.Parents() {
+		var kind = "string"
+		switch {
+		case parent.UUID:
+			kind = "uuid"
+		case parent.Integer:
+			kind = "integer"
+		}
+		got = append(got, parent.By+" "+parent.Name+" "+kind+" "+parent.RoutePath)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestParentTemplates(t *testing.T) {
+	var data = parseTable(t, config.DefaultProject(), parentsTable)
+
+	containsAll(t, "protos/template.proto", render(t, data, "protos/template.proto"),
+		"rpc GetListTByClient(GetListTByClientRequest) returns (GetListTResponse) {}",
+		"optional int32 client_id = 6;",
+		"optional string owner_id = 7;",
+		"message GetListTByOwnerRequest {\n    string owner_id = 1;",
+	)
+	containsAll(t, "storage/template_storage.txt", render(t, data, "storage/template_storage.txt"),
+		"if req.ClientId != nil {\n\t\tfilter += ` AND  client_id = :client_id`\n\t\tparams[\"client_id\"] = req.GetClientId()",
+		"if req.Warehouse != nil {",
+	)
+	containsAll(t, "handlers/template.txt", render(t, data, "handlers/template.txt"),
+		"// @Param client_id query integer false \"client_id\"",
+		"parsed, err := strconv.ParseInt(value, 10, 32)",
+		"if !util.IsValidUUID(value) {",
+		"v1/client/{client_id}/t [GET]",
+	)
+}
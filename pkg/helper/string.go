@@ -1,26 +1,13 @@
 package helper
 
 import (
+	"go/token"
 	"strings"
-)
-
-func RemoveEmptyRows(input string) string {
-	// Split the input string into lines
-	lines := strings.Split(input, "\n")
-
-	// Filter out the empty lines
-	var nonEmptyLines []string
-	for _, line := range lines {
-		if strings.TrimSpace(line) != "" && !strings.Contains(line, "--") {
-			nonEmptyLines = append(nonEmptyLines, line)
-		}
-	}
-
-	// Join the non-empty lines back together
-	output := strings.Join(nonEmptyLines, "\n")
+	"unicode"
 
-	return output
-}
+	"githubc.com/asadbekGo/generate-code/pkg/types"
+	"githubc.com/asadbekGo/generate-code/schema"
+)
 
 func Pluralize(word string) string {
 	// Check for common pluralization rules
@@ -34,31 +21,6 @@ func Pluralize(word string) string {
 	return word + "s"
 }
 
-func SQLToGoType(sqlType string) string {
-	// Convert PostgreSQL types to Go types
-	sqlType = strings.ToLower(sqlType)
-	switch sqlType {
-	case "uuid":
-		return "string"
-	case "varchar", "text", "char", "character varying":
-		return "string"
-	case "integer", "int", "smallint", "number":
-		return "int32"
-	case "bigint":
-		return "int64"
-	case "float", "double precision", "numeric", "decimal", "real":
-		return "double"
-	case "boolean":
-		return "bool"
-	case "date", "timestamp", "time":
-		return "string"
-	case "json", "jsonb":
-		return "interface{}" // Handle JSON types
-	default:
-		return "interface{}" // Fallback to interface{} for unknown types
-	}
-}
-
 func SnakeToCamel(s string) string {
 	// Split the string by underscores
 	parts := strings.Split(s, "_")
@@ -71,3 +33,88 @@ func SnakeToCamel(s string) string {
 	// Join the parts back together
 	return strings.Join(parts, "")
 }
+
+func SnakeToPascal(s string) string {
+	var camel = SnakeToCamel(s)
+	if camel == "" {
+		return camel
+	}
+
+	return strings.ToUpper(camel[:1]) + camel[1:]
+}
+
+// GoVarName is the lower camel case identifier of s, suffixed with "_" when
+// it would clash with a Go keyword, e.g. a "type" column.
+func GoVarName(s string) string {
+	var name = SnakeToCamel(s)
+	if token.IsKeyword(name) {
+		return name + "_"
+	}
+
+	return name
+}
+
+func SnakeToKebab(s string) string {
+	return strings.ReplaceAll(s, "_", "-")
+}
+
+func CamelToSnake(s string) string {
+	var out []rune
+	for i, r := range s {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				out = append(out, '_')
+			}
+			r = unicode.ToLower(r)
+		}
+		out = append(out, r)
+	}
+
+	return string(out)
+}
+
+// GoType is the Go type of the column with the default type mapping.
+func GoType(column *schema.Column) string {
+	goType, _ := types.Go(types.Proto(column))
+	return goType
+}
+
+// ProtoType is the proto type of the column with the default type mapping.
+func ProtoType(column *schema.Column) string {
+	return types.Proto(column)
+}
+
+// EnumValueName is the proto enum value of an SQL enum label, prefixed with
+// the type name as proto enum values share the package scope, e.g.
+// STATUS_TRANSACTION_OTHER.
+func EnumValueName(enum *schema.Enum, value string) string {
+	return strings.ToUpper(enum.Name + "_" + enumLabel(value))
+}
+
+// EnumConstName is the Go constant holding an SQL enum label, e.g.
+// StatusTransactionOther.
+func EnumConstName(enum *schema.Enum, value string) string {
+	return SnakeToPascal(enum.Name) + SnakeToPascal(strings.ToLower(enumLabel(value)))
+}
+
+// enumLabel replaces everything but letters and digits of an enum label
+// with underscores.
+func enumLabel(value string) string {
+	return strings.Map(func(r rune) rune {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			return r
+		}
+		return '_'
+	}, value)
+}
+
+// Lines splits a comment into its non-empty lines.
+func Lines(comment string) []string {
+	var lines []string
+	for _, line := range strings.Split(comment, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
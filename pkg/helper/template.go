@@ -0,0 +1,97 @@
+package helper
+
+import (
+	"bytes"
+	"strings"
+	"text/template"
+
+	"githubc.com/asadbekGo/generate-code/config"
+	"githubc.com/asadbekGo/generate-code/schema"
+)
+
+// TemplateData is what every template is rendered against.
+type TemplateData struct {
+	Project config.Project
+	Group   config.Group
+	Options config.TableConfig
+	Schema  *schema.Schema
+	Table   *schema.Table
+	// Enum is set while rendering the files of an enum type.
+	Enum *schema.Enum
+}
+
+func NewTemplateData(project config.Project, s *schema.Schema, table *schema.Table) TemplateData {
+	return TemplateData{
+		Project: project,
+		Group:   project.Group(table.Name),
+		Options: project.Table(table.Name),
+		Schema:  s,
+		Table:   table,
+	}
+}
+
+// EnumOf returns the enum type of the column or nil.
+func (d TemplateData) EnumOf(column *schema.Column) *schema.Enum {
+	if column.Array {
+		return nil
+	}
+	return d.Schema.Enum(column.Type)
+}
+
+// Enums returns the enum types used by the fields of the table.
+func (d TemplateData) Enums() []*schema.Enum {
+	var enums []*schema.Enum
+	for _, column := range d.Table.Fields() {
+		enum := d.EnumOf(column)
+		if enum != nil && !containsEnum(enums, enum) {
+			enums = append(enums, enum)
+		}
+	}
+	return enums
+}
+
+func containsEnum(enums []*schema.Enum, enum *schema.Enum) bool {
+	for _, e := range enums {
+		if e == enum {
+			return true
+		}
+	}
+	return false
+}
+
+var TemplateFuncs = template.FuncMap{
+	"camel":     SnakeToCamel,
+	"varName":   GoVarName,
+	"pascal":    SnakeToPascal,
+	"snake":     CamelToSnake,
+	"kebab":     SnakeToKebab,
+	"plural":    Pluralize,
+	"upper":     strings.ToUpper,
+	"lower":     strings.ToLower,
+	"goType":    GoType,
+	"protoType": ProtoType,
+	"enumValue": EnumValueName,
+	"enumConst": EnumConstName,
+	"add":       func(a, b int) int { return a + b },
+	"lines":     Lines,
+}
+
+func RenderTemplate(filename string, data interface{}) (string, error) {
+	body, err := ReadFile(filename)
+	if err != nil {
+		return "", err
+	}
+
+	tmpl, err := template.New(filename).Funcs(TemplateFuncs).Parse(string(body))
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, data)
+	if err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
@@ -0,0 +1,121 @@
+package helper
+
+import (
+	"go/format"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"githubc.com/asadbekGo/generate-code/config"
+	"githubc.com/asadbekGo/generate-code/pkg/parser"
+	"githubc.com/asadbekGo/generate-code/schema"
+)
+
+// parseTable parses sql and returns the template data of its table t.
+func parseTable(t *testing.T, project config.Project, sql string) TemplateData {
+	t.Helper()
+
+	s, err := parser.Parse("test.sql", sql)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewTemplateData(project, s, s.Table("t"))
+}
+
+// render renders a template of the repository, e.g. "storage/enum.txt",
+// rendered Go files have to be formattable.
+func render(t *testing.T, data TemplateData, filename string) string {
+	t.Helper()
+
+	out, err := RenderTemplate(filepath.Join("..", "..", filename), data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.HasPrefix(out, "package ") {
+		if _, err := format.Source([]byte(out)); err != nil {
+			t.Fatalf("%s: %v\n%s", filename, err, out)
+		}
+	}
+	return out
+}
+
+// containsAll reports the strings of want missing from got.
+func containsAll(t *testing.T, filename, got string, want ...string) {
+	t.Helper()
+
+	for _, s := range want {
+		if !strings.Contains(got, s) {
+			t.Errorf("%s does not contain %q", filename, s)
+		}
+	}
+}
+
+func TestEnumTemplate(t *testing.T) {
+	var data = parseTable(t, config.DefaultProject(), `
+CREATE TYPE status AS ENUM ('new', 'in progress');
+CREATE TABLE t (id UUID PRIMARY KEY, status status);
+`)
+	data.Enum = data.Schema.Enum("status")
+
+	containsAll(t, "storage/enum.txt", render(t, data, "storage/enum.txt"),
+		`StatusNew = "new"`,
+		`StatusInProgress = "in progress"`,
+		"StatusInProgress: storehouse_client_service.Status_STATUS_IN_PROGRESS,",
+		"storehouse_client_service.Status_STATUS_IN_PROGRESS: StatusInProgress,",
+		"func statusToSQL(value storehouse_client_service.Status) sql.NullString {",
+		"func statusFromSQL(value sql.NullString) storehouse_client_service.Status {",
+	)
+	containsAll(t, "protos/enum.proto", render(t, data, "protos/enum.proto"),
+		"enum Status {",
+		"STATUS_UNSPECIFIED = 0;",
+		"STATUS_NEW = 1;",
+		"STATUS_IN_PROGRESS = 2;",
+	)
+}
+
+func TestEnumNames(t *testing.T) {
+	var enum = &schema.Enum{Name: "status_transaction"}
+
+	var tests = []struct {
+		value, name, constName string
+	}{
+		{"other", "STATUS_TRANSACTION_OTHER", "StatusTransactionOther"},
+		{"in progress", "STATUS_TRANSACTION_IN_PROGRESS", "StatusTransactionInProgress"},
+		{"Paid-Out", "STATUS_TRANSACTION_PAID_OUT", "StatusTransactionPaidOut"},
+	}
+
+	for _, test := range tests {
+		if got := EnumValueName(enum, test.value); got != test.name {
+			t.Errorf("EnumValueName(%q) = %q, want %q", test.value, got, test.name)
+		}
+		if got := EnumConstName(enum, test.value); got != test.constName {
+			t.Errorf("EnumConstName(%q) = %q, want %q", test.value, got, test.constName)
+		}
+	}
+}
+
+func TestCommentTemplates(t *testing.T) {
+	const comments = `
+COMMENT ON TABLE t IS 'Links of a chapter.
+Second line.';
+COMMENT ON COLUMN t.chapter_id IS 'The "linked" chapter';
+`
+
+	var data = parseTable(t, config.DefaultProject(), `
+CREATE TABLE chapter (id UUID PRIMARY KEY, code TEXT);
+CREATE TABLE t (chapter_id UUID REFERENCES chapter(id), other_id UUID REFERENCES chapter(id), PRIMARY KEY (chapter_id, other_id));
+`+comments)
+
+	containsAll(t, "handlers/template.txt", render(t, data, "handlers/template.txt"),
+		"// @Description Create T\n// @Description Links of a chapter.\n// @Description Second line.\n",
+		`// @Param chapter_id path string true "The 'linked' chapter"`,
+		`// @Param other_id path string true "other_id"`,
+	)
+	containsAll(t, "handlers/join.txt", render(t, data, "handlers/join.txt"),
+		"// @Description Attach T, links already attached are skipped\n// @Description Links of a chapter.\n",
+		`path string true "The 'linked' chapter"`,
+	)
+	containsAll(t, "protos/join.proto", render(t, data, "protos/join.proto"),
+		"// Links of a chapter.\n// Second line.\nmessage T {\n    // The \"linked\" chapter\n    string chapter_id = 1;",
+	)
+}
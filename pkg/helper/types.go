@@ -0,0 +1,209 @@
+package helper
+
+import (
+	"fmt"
+
+	"githubc.com/asadbekGo/generate-code/pkg/types"
+	"githubc.com/asadbekGo/generate-code/schema"
+)
+
+// ProtoType is the proto type of the column. Enum columns use the
+// generated enum, otherwise the column override of the table wins over
+// the type override of the project and the default mapping.
+func (d TemplateData) ProtoType(column *schema.Column) string {
+	if enum := d.EnumOf(column); enum != nil {
+		return SnakeToPascal(enum.Name)
+	}
+
+	if protoType, ok := d.Options.Columns[column.Name]; ok {
+		return protoType
+	}
+
+	if protoType, ok := d.Project.Types[column.Type]; ok {
+		if column.Array {
+			return types.Repeated(protoType)
+		}
+		return protoType
+	}
+
+	return types.Proto(column)
+}
+
+// GoType is the Go type of the proto field of the column.
+func (d TemplateData) GoType(column *schema.Column) string {
+	if enum := d.EnumOf(column); enum != nil {
+		return d.Group.GoPackageName() + "." + SnakeToPascal(enum.Name)
+	}
+
+	goType, _ := types.Go(d.ProtoType(column))
+	return goType
+}
+
+// ScanType is the type of the variable the column is scanned into.
+func (d TemplateData) ScanType(column *schema.Column) string {
+	if d.EnumOf(column) != nil {
+		return "sql.NullString"
+	}
+
+	switch goType := d.GoType(column); goType {
+	case "string":
+		return "sql.NullString"
+	case "int32":
+		return "sql.NullInt32"
+	case "int64", "uint32", "uint64":
+		return "sql.NullInt64"
+	case "float64", "float32":
+		return "sql.NullFloat64"
+	case "bool":
+		return "sql.NullBool"
+	case "*structpb.Struct":
+		return "map[string]interface{}"
+	default:
+		// bytes and arrays are scanned as they are
+		return goType
+	}
+}
+
+// ScanValue is the expression turning the scanned variable of the column
+// into its proto field value.
+func (d TemplateData) ScanValue(column *schema.Column) string {
+	var name = GoVarName(column.Name)
+
+	if enum := d.EnumOf(column); enum != nil {
+		return SnakeToCamel(enum.Name) + "FromSQL(" + name + ")"
+	}
+
+	switch goType := d.GoType(column); goType {
+	case "string":
+		return name + ".String"
+	case "int32":
+		return name + ".Int32"
+	case "int64":
+		return name + ".Int64"
+	case "uint32", "uint64":
+		return goType + "(" + name + ".Int64)"
+	case "float64":
+		return name + ".Float64"
+	case "float32":
+		return "float32(" + name + ".Float64)"
+	case "bool":
+		return name + ".Bool"
+	default:
+		return name
+	}
+}
+
+// SelectColumn is the column in a SELECT list, types without a string
+// representation in Go are cast to text when they are mapped to string.
+func (d TemplateData) SelectColumn(column *schema.Column) string {
+	if d.EnumOf(column) == nil && !types.IsText(column.Type) {
+		switch d.GoType(column) {
+		case "string":
+			return column.Name + "::text"
+		case "[]string":
+			return column.Name + "::text[]"
+		}
+	}
+
+	return column.Name
+}
+
+// SQLParam is the query argument written to the column from req.
+func (d TemplateData) SQLParam(column *schema.Column) string {
+	var getter = "req.Get" + SnakeToPascal(column.Name) + "()"
+
+	if enum := d.EnumOf(column); enum != nil {
+		return SnakeToCamel(enum.Name) + "ToSQL(" + getter + ")"
+	}
+
+	if d.Optional(column) {
+		// nil is written as NULL
+		return "req." + SnakeToPascal(column.Name)
+	}
+
+	if d.GoType(column) == "*structpb.Struct" {
+		// the map converted from the struct by the template, nil for NULL
+		return GoVarName(column.Name)
+	}
+
+	return getter
+}
+
+// Optional reports whether the column is a proto3 optional field, that
+// is a nullable scalar. Enums, bytes, arrays and messages tell NULL apart
+// without it.
+func (d TemplateData) Optional(column *schema.Column) bool {
+	return column.Nullable() && d.scalar(column)
+}
+
+// CreateOptional reports whether the column is optional in the Create
+// request, columns with a default are optional there as well.
+func (d TemplateData) CreateOptional(column *schema.Column) bool {
+	return (column.Nullable() || column.HasDefault) && d.scalar(column)
+}
+
+// CreateParam is the Create query argument of the column, unset optional
+// fields are written as NULL.
+func (d TemplateData) CreateParam(column *schema.Column) string {
+	if d.EnumOf(column) == nil && d.CreateOptional(column) {
+		return "req." + SnakeToPascal(column.Name)
+	}
+	return d.SQLParam(column)
+}
+
+// InsertValue is the VALUES expression of the column bound to param, e.g.
+// $2, columns with a default fall back to it when the argument is NULL.
+func (d TemplateData) InsertValue(column *schema.Column, param string) string {
+	if column.Default == "" {
+		return param
+	}
+
+	return fmt.Sprintf("COALESCE(%s::%s, %s)", param, castType(column), column.Default)
+}
+
+// castType is the SQL type a query argument of the column is cast to,
+// without the length that would truncate char and bit values.
+func castType(column *schema.Column) string {
+	var sqlType = column.Type
+	switch sqlType {
+	case "char":
+		sqlType = "bpchar"
+	case "bit":
+		sqlType = "varbit"
+	}
+
+	if column.Array {
+		return sqlType + "[]"
+	}
+	return sqlType
+}
+
+// scalar reports whether the proto field of the column is a scalar that
+// needs optional to tell an unset value apart.
+func (d TemplateData) scalar(column *schema.Column) bool {
+	if d.EnumOf(column) != nil {
+		return false
+	}
+
+	switch d.GoType(column) {
+	case "string", "int32", "int64", "uint32", "uint64", "float32", "float64", "bool":
+		return true
+	}
+	return false
+}
+
+// PointerField reports whether the proto field of the column is a pointer
+// the storage sets only for values that are not NULL.
+func (d TemplateData) PointerField(column *schema.Column) bool {
+	return d.Optional(column) || d.GoType(column) == "*structpb.Struct"
+}
+
+// UsesStruct reports whether a field of the table is a google.protobuf.Struct.
+func (d TemplateData) UsesStruct() bool {
+	for _, column := range d.Table.Fields() {
+		if d.GoType(column) == "*structpb.Struct" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,165 @@
+package helper
+
+import (
+	"reflect"
+	"testing"
+
+	"githubc.com/asadbekGo/generate-code/config"
+)
+
+const typesTable = `
+CREATE TYPE status AS ENUM ('new', 'done');
+CREATE TABLE t (
+	id UUID PRIMARY KEY,
+	status status,
+	state status NOT NULL,
+	note TEXT,
+	title TEXT NOT NULL,
+	count INT NOT NULL DEFAULT 0,
+	price NUMERIC,
+	data JSONB,
+	tags TEXT[],
+	code CHAR(3) DEFAULT 'abc',
+	flags TEXT[] DEFAULT '{}'
+);
+`
+
+func TestColumnTypes(t *testing.T) {
+	var data = parseTable(t, config.DefaultProject(), typesTable)
+
+	var tests = []struct {
+		column    string
+		protoType string
+		scanType  string
+		scanValue string
+		sqlParam  string
+		optional  bool
+	}{
+		{"status", "Status", "sql.NullString", "statusFromSQL(status)", "statusToSQL(req.GetStatus())", false},
+		{"state", "Status", "sql.NullString", "statusFromSQL(state)", "statusToSQL(req.GetState())", false},
+		{"note", "string", "sql.NullString", "note.String", "req.Note", true},
+		{"title", "string", "sql.NullString", "title.String", "req.GetTitle()", false},
+		{"count", "int32", "sql.NullInt32", "count.Int32", "req.GetCount()", false},
+		{"price", "double", "sql.NullFloat64", "price.Float64", "req.Price", true},
+		{"data", "google.protobuf.Struct", "map[string]interface{}", "data", "data", false},
+		{"tags", "repeated string", "[]string", "tags", "req.GetTags()", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.column, func(t *testing.T) {
+			var column = data.Table.Column(test.column)
+			if got := data.ProtoType(column); got != test.protoType {
+				t.Errorf("ProtoType = %q, want %q", got, test.protoType)
+			}
+			if got := data.ScanType(column); got != test.scanType {
+				t.Errorf("ScanType = %q, want %q", got, test.scanType)
+			}
+			if got := data.ScanValue(column); got != test.scanValue {
+				t.Errorf("ScanValue = %q, want %q", got, test.scanValue)
+			}
+			if got := data.SQLParam(column); got != test.sqlParam {
+				t.Errorf("SQLParam = %q, want %q", got, test.sqlParam)
+			}
+			if got := data.Optional(column); got != test.optional {
+				t.Errorf("Optional = %v, want %v", got, test.optional)
+			}
+		})
+	}
+
+	if got := data.GoType(data.Table.Column("status")); got != "storehouse_client_service.Status" {
+		t.Errorf("GoType(status) = %q", got)
+	}
+}
+
+func TestOptionalTemplates(t *testing.T) {
+	var data = parseTable(t, config.DefaultProject(), typesTable)
+
+	containsAll(t, "protos/template.proto", render(t, data, "protos/template.proto"),
+		"Status status = 2;",
+		"optional string note = 4;",
+		"string title = 5;",
+		"optional double price = 7;",
+		"repeated string tags = 9;",
+	)
+
+	// NULL is scanned into the sql.Null* variable and only set when valid,
+	// a nil optional field is written as NULL
+	containsAll(t, "storage/template_storage.txt", render(t, data, "storage/template_storage.txt"),
+		"note sql.NullString",
+		"if note.Valid {\n\t\tvalue := note.String\n\t\tresp.Note = &value",
+		"req.Note,",
+	)
+}
+
+func TestInsertValues(t *testing.T) {
+	var tests = []struct {
+		name    string
+		sql     string
+		columns []string
+		values  string
+	}{
+		{
+			name:    "defaults fall back with COALESCE",
+			sql:     typesTable,
+			columns: []string{"id", "status", "state", "note", "title", "count", "price", "data", "tags", "code", "flags"},
+			values:  "$1, $2, $3, $4, $5, COALESCE($6::integer, 0), $7, $8, $9, COALESCE($10::bpchar, 'abc'), COALESCE($11::text[], '{}')",
+		},
+		{
+			name:    "key generated by the database",
+			sql:     "CREATE TABLE t (id SERIAL PRIMARY KEY, name TEXT, created_at TIMESTAMP DEFAULT now(), updated_at TIMESTAMP);",
+			columns: []string{"name", "updated_at"},
+			values:  "$1, now()",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var data = parseTable(t, config.DefaultProject(), test.sql)
+			if got := data.InsertColumns(); !reflect.DeepEqual(got, test.columns) {
+				t.Errorf("InsertColumns = %q, want %q", got, test.columns)
+			}
+			if got := data.InsertValues(); got != test.values {
+				t.Errorf("InsertValues = %q, want %q", got, test.values)
+			}
+		})
+	}
+
+	var data = parseTable(t, config.DefaultProject(), typesTable)
+	if got := data.CreateParam(data.Table.Column("count")); got != "req.Count" {
+		t.Errorf("CreateParam(count) = %q, an unset count is written as NULL", got)
+	}
+}
+
+func TestProtoTypeOverrides(t *testing.T) {
+	var project = config.DefaultProject()
+	project.Types = map[string]string{"numeric": "string", "integer": "int64", "status": "string"}
+	project.Tables = map[string]config.TableConfig{"t": {Columns: map[string]string{"price": "sfixed64"}}}
+
+	var data = parseTable(t, project, `
+CREATE TYPE status AS ENUM ('new');
+CREATE TABLE t (id UUID PRIMARY KEY, price NUMERIC, cost NUMERIC, count INT, counts INT[], status status);
+`)
+
+	var tests = []struct {
+		column string
+		want   string
+		goType string
+	}{
+		{"price", "sfixed64", "int64"},
+		{"cost", "string", "string"},
+		{"count", "int64", "int64"},
+		{"counts", "repeated int64", "[]int64"},
+		{"status", "Status", "storehouse_client_service.Status"},
+		{"id", "string", "string"},
+	}
+
+	for _, test := range tests {
+		var column = data.Table.Column(test.column)
+		if got := data.ProtoType(column); got != test.want {
+			t.Errorf("ProtoType(%s) = %q, want %q", test.column, got, test.want)
+		}
+		if got := data.GoType(column); got != test.goType {
+			t.Errorf("GoType(%s) = %q, want %q", test.column, got, test.goType)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package helper
+
+import (
+	"fmt"
+	"strings"
+
+	"githubc.com/asadbekGo/generate-code/schema"
+)
+
+// Lookup is a unique constraint or index a single row is read by, e.g.
+// GetClientByPhoneNumber served on /client/by-phone-number/:phone_number.
+type Lookup struct {
+	// By names the lookup in RPC and method names, e.g. PhoneNumber.
+	By          string
+	Columns     []*schema.Column
+	Params      []Param
+	RoutePath   string
+	SwaggerPath string
+}
+
+// Lookups returns the unique constraints and indexes other than the key
+// whose columns are strings or integers, in the order they are declared.
+// Partial indexes are left out, they are unique among some rows only.
+func (d TemplateData) Lookups() []Lookup {
+	var (
+		lookups []Lookup
+		seen    = map[string]bool{}
+	)
+
+	for _, index := range d.Table.Indexes {
+		if !index.Unique || index.Expression || index.Predicate != "" || len(index.Columns) == 0 || sameColumns(index.Columns, d.Table.KeyColumns()) {
+			continue
+		}
+
+		var (
+			lookup = Lookup{RoutePath: "/" + SnakeToKebab(d.Table.Name), SwaggerPath: "/" + SnakeToKebab(d.Table.Name)}
+			names  []string
+		)
+
+		for _, name := range index.Columns {
+			var column = d.Table.Column(name)
+			if column == nil || column.Array || d.EnumOf(column) != nil {
+				lookup.Columns = nil
+				break
+			}
+			if d.GoType(column) != "string" && !d.IsInteger(column) {
+				lookup.Columns = nil
+				break
+			}
+			lookup.Columns = append(lookup.Columns, column)
+			lookup.Params = append(lookup.Params, d.newParam(column.Name, column.Name, column))
+			names = append(names, column.Name)
+		}
+		if len(lookup.Columns) != len(index.Columns) {
+			continue
+		}
+
+		lookup.By = SnakeToPascal(strings.Join(names, "_and_"))
+		if seen[lookup.By] {
+			continue
+		}
+		seen[lookup.By] = true
+
+		lookup.RoutePath += "/by-" + SnakeToKebab(strings.Join(names, "_and_"))
+		lookup.SwaggerPath = lookup.RoutePath
+		for _, param := range lookup.Params {
+			lookup.RoutePath += "/:" + param.Name
+			lookup.SwaggerPath += "/{" + param.Name + "}"
+		}
+
+		lookups = append(lookups, lookup)
+	}
+
+	return lookups
+}
+
+// Condition is the WHERE condition matching the columns bound to $1 and
+// the following parameters.
+func (l Lookup) Condition() string {
+	var conditions []string
+	for i, column := range l.Columns {
+		conditions = append(conditions, fmt.Sprintf("%s = $%d", column.Name, i+1))
+	}
+	return strings.Join(conditions, " AND ")
+}
+
+// Fields are the fields of the lookup request literal read from the
+// parsed path parameters.
+func (l Lookup) Fields() string {
+	var fields []string
+	for _, param := range l.Params {
+		fields = append(fields, SnakeToPascal(param.Column.Name)+": "+param.Value())
+	}
+	return strings.Join(fields, ", ")
+}
+
+// LookupRules returns the checks of the lookup request.
+func (d TemplateData) LookupRules(lookup Lookup) []Rule {
+	return d.rules(lookup.Columns, false)
+}
+
+// UniqueConstraints maps the unique constraints and indexes, the primary
+// key included, to the columns they cover, e.g. "client_phone_number_key":
+// "phone_number". A unique violation names the columns with it.
+func (d TemplateData) UniqueConstraints() map[string]string {
+	var constraints = map[string]string{}
+
+	if len(d.Table.PrimaryKey) > 0 {
+		constraints[d.Table.Name+"_pkey"] = strings.Join(d.Table.PrimaryKey, ", ")
+	}
+	for _, index := range d.Table.Indexes {
+		if index.Unique && !index.Expression {
+			constraints[index.Name] = strings.Join(index.Columns, ", ")
+		}
+	}
+
+	return constraints
+}
+
+func sameColumns(names []string, columns []*schema.Column) bool {
+	if len(names) != len(columns) {
+		return false
+	}
+	for _, column := range columns {
+		if !contains(names, column.Name) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,70 @@
+package helper
+
+import (
+	"reflect"
+	"testing"
+
+	"githubc.com/asadbekGo/generate-code/config"
+	"githubc.com/asadbekGo/generate-code/pkg/parser"
+)
+
+func TestLookups(t *testing.T) {
+	var tests = []struct {
+		name string
+		sql  string
+		want []string
+	}{
+		{
+			name: "unique column",
+			sql:  "CREATE TABLE t (id UUID PRIMARY KEY, phone TEXT UNIQUE);",
+			want: []string{"Phone /t/by-phone/:phone"},
+		},
+		{
+			name: "composite unique constraint",
+			sql:  "CREATE TABLE t (id UUID PRIMARY KEY, code TEXT, year INT, UNIQUE (code, year));",
+			want: []string{"CodeAndYear /t/by-code-and-year/:code/:year"},
+		},
+		{
+			name: "primary key",
+			sql:  "CREATE TABLE t (id UUID PRIMARY KEY, code TEXT); CREATE UNIQUE INDEX ON t (id);",
+		},
+		{
+			name: "partial unique index",
+			sql:  "CREATE TABLE t (id UUID PRIMARY KEY, email TEXT, deleted_at TIMESTAMP); CREATE UNIQUE INDEX t_email_idx ON t (email) WHERE deleted_at IS NULL;",
+		},
+		{
+			name: "expression index",
+			sql:  "CREATE TABLE t (id UUID PRIMARY KEY, email TEXT); CREATE UNIQUE INDEX ON t (lower(email));",
+		},
+		{
+			name: "index that is not unique",
+			sql:  "CREATE TABLE t (id UUID PRIMARY KEY, email TEXT); CREATE INDEX ON t (email);",
+		},
+		{
+			name: "unsupported column type",
+			sql:  "CREATE TABLE t (id UUID PRIMARY KEY, active BOOLEAN UNIQUE, tags TEXT[] UNIQUE);",
+		},
+		{
+			name: "same columns twice",
+			sql:  "CREATE TABLE t (id UUID PRIMARY KEY, phone TEXT UNIQUE); CREATE UNIQUE INDEX t_phone_idx ON t (phone);",
+			want: []string{"Phone /t/by-phone/:phone"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s, err := parser.Parse("test.sql", test.sql)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []string
+			for _, lookup := range NewTemplateData(config.Project{}, s, s.Table("t")).Lookups() {
+				got = append(got, lookup.By+" "+lookup.RoutePath)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,365 @@
+package helper
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"githubc.com/asadbekGo/generate-code/schema"
+)
+
+// Rule is a check of a request field the service runs before the storage
+// is called, generated from a constraint of the column.
+type Rule struct {
+	// Field is the proto field name reported in the violation.
+	Field string
+	// Init is an optional statement run before Condition, e.g. a uuid.Parse.
+	Init string
+	// Condition is the Go expression that is true for an invalid value.
+	Condition   string
+	Description string
+}
+
+// PatchField is the check of a field an UpdatePatch request may set. The
+// patch is a google.protobuf.Struct, its value is asserted to Type and
+// checked by Rules as value.
+type PatchField struct {
+	Field    string
+	Nullable bool
+	// Type is the Go type of the JSON value, e.g. float64 for all numbers.
+	Type string
+	// Kind names Type in the violation, e.g. "a number".
+	Kind  string
+	Rules []Rule
+}
+
+var (
+	checkBetween    = regexp.MustCompile(`(?i)^(\S+)\s+BETWEEN\s+(\S+)\s+AND\s+(\S+)$`)
+	checkIn         = regexp.MustCompile(`(?i)^(\S+)\s+IN\s*\((.*)\)$`)
+	checkCompare    = regexp.MustCompile(`^(.+?)\s*(>=|<=|<>|!=|=|>|<)\s*(\S+)$`)
+	checkLength     = regexp.MustCompile(`(?i)^(?:char_length|character_length|length)\s*\(\s*(\S+?)\s*\)$`)
+	checkAnd        = regexp.MustCompile(`(?i)\s+AND\s+`)
+	checkNumber     = regexp.MustCompile(`^-?\d+(\.\d+)?$`)
+	checkString     = regexp.MustCompile(`^'((?:[^']|'')*)'$`)
+	checkCast       = regexp.MustCompile(`::[a-z ]+$`)
+	checkIdentifier = regexp.MustCompile(`^"?([a-zA-Z_][a-zA-Z0-9_]*)"?$`)
+)
+
+// plainText are the types any string can be written to, the empty string
+// of other types mapped to string is not a valid value.
+var plainText = map[string]bool{
+	"char":    true,
+	"varchar": true,
+	"text":    true,
+	"citext":  true,
+	"name":    true,
+}
+
+// CreateRules returns the checks of the Create request.
+func (d TemplateData) CreateRules() []Rule {
+	return d.rules(d.CreateFields(), true)
+}
+
+// UpdateRules returns the checks of the Update request, the key columns
+// followed by the writable fields.
+func (d TemplateData) UpdateRules() []Rule {
+	return d.rules(append(d.Table.KeyColumns(), d.Table.WritableFields()...), false)
+}
+
+// KeyRules returns the checks of the PrimaryKey message.
+func (d TemplateData) KeyRules() []Rule {
+	return d.rules(d.Table.KeyColumns(), false)
+}
+
+// PatchFields returns the checks of the fields an UpdatePatch request may
+// set, the writable fields.
+func (d TemplateData) PatchFields() []PatchField {
+	var fields []PatchField
+
+	for _, column := range d.Table.WritableFields() {
+		var (
+			field  = PatchField{Field: column.Name, Nullable: column.Nullable()}
+			goType = d.GoType(column)
+		)
+
+		switch {
+		case d.EnumOf(column) != nil:
+			var labels []string
+			for _, value := range d.EnumOf(column).Values {
+				labels = append(labels, "value == "+strconv.Quote(value))
+			}
+			field.Type, field.Kind = "string", "a string"
+			field.Rules = []Rule{{
+				Field:       column.Name,
+				Condition:   "!(" + strings.Join(labels, " || ") + ")",
+				Description: "must be one of " + strings.Join(d.EnumOf(column).Values, ", "),
+			}}
+			fields = append(fields, field)
+			continue
+		case column.Array:
+			field.Type, field.Kind = "[]interface{}", "a list"
+		case goType == "bool":
+			field.Type, field.Kind = "bool", "a boolean"
+		case goType == "*structpb.Struct":
+			field.Type, field.Kind = "map[string]interface{}", "an object"
+		case goType == "string" || goType == "[]byte":
+			field.Type, field.Kind = "string", "a string"
+		default:
+			field.Type, field.Kind = "float64", "a number"
+		}
+
+		switch {
+		case column.Array:
+		case strings.HasPrefix(goType, "int"):
+			field.Rules = append(field.Rules, Rule{
+				Field:       column.Name,
+				Condition:   "value != float64(int64(value))",
+				Description: "must be an integer",
+			})
+		case strings.HasPrefix(goType, "uint"):
+			field.Rules = append(field.Rules, Rule{
+				Field:       column.Name,
+				Condition:   "value < 0 || value != float64(uint64(value))",
+				Description: "must be a non-negative integer",
+			})
+		}
+
+		// a null is reported before the value is asserted, only the empty
+		// string is left to be required
+		var required = !column.Nullable() && goType == "string"
+		field.Rules = append(field.Rules, d.columnRules(column, "value", required, false)...)
+		fields = append(fields, field)
+	}
+
+	return fields
+}
+
+// RulesUse reports whether a generated check calls the package, e.g. uuid.
+func (d TemplateData) RulesUse(pkg string) bool {
+	var rules = d.KeyRules()
+	for _, lookup := range d.Lookups() {
+		rules = append(rules, d.LookupRules(lookup)...)
+	}
+	if !d.Options.ReadOnly {
+		rules = append(rules, d.CreateRules()...)
+		rules = append(rules, d.UpdateRules()...)
+		for _, field := range d.PatchFields() {
+			rules = append(rules, field.Rules...)
+		}
+	}
+
+	for _, rule := range rules {
+		if strings.Contains(rule.Init+" "+rule.Condition, pkg+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// rules returns the checks of the columns set from a request. Columns with
+// a default may be left unset in Create.
+func (d TemplateData) rules(columns []*schema.Column, create bool) []Rule {
+	var rules []Rule
+
+	for _, column := range columns {
+		var (
+			required = !column.Nullable() && !(create && column.HasDefault)
+			optional = d.PointerField(column) || create && d.CreateOptional(column)
+		)
+		rules = append(rules, d.columnRules(column, "req.Get"+SnakeToPascal(column.Name)+"()", required, optional)...)
+	}
+
+	return rules
+}
+
+// columnRules returns the checks of the column read by getter, the checks
+// of an optional field hold for a set value only.
+func (d TemplateData) columnRules(column *schema.Column, getter string, required, optional bool) []Rule {
+	var (
+		rules  []Rule
+		goType = d.GoType(column)
+	)
+
+	if enum := d.EnumOf(column); enum != nil {
+		var enumType = d.Group.GoPackageName() + "." + SnakeToPascal(enum.Name)
+		rules = append(rules, Rule{
+			Field:       column.Name,
+			Init:        "_, ok := " + enumType + "_name[int32(" + getter + ")]",
+			Condition:   "!ok",
+			Description: "must be one of " + strings.Join(enum.Values, ", "),
+		})
+		if required {
+			rules = append(rules, Rule{
+				Field:       column.Name,
+				Condition:   getter + " == " + enumType + "_" + strings.ToUpper(enum.Name) + "_UNSPECIFIED",
+				Description: "is required",
+			})
+		}
+		return rules
+	}
+
+	switch {
+	case required && goType == "string" && !plainText[column.Type]:
+		rules = append(rules, Rule{Field: column.Name, Condition: getter + ` == ""`, Description: "is required"})
+	case required && goType == "*structpb.Struct":
+		rules = append(rules, Rule{Field: column.Name, Condition: getter + " == nil", Description: "is required"})
+	}
+
+	if column.Type == "uuid" && goType == "string" {
+		rules = append(rules, Rule{
+			Field:       column.Name,
+			Init:        "_, err := uuid.Parse(" + getter + ")",
+			Condition:   getter + ` != "" && err != nil`,
+			Description: "must be a valid uuid",
+		})
+	}
+
+	if column.Length > 0 && (column.Type == "varchar" || column.Type == "char") && goType == "string" {
+		rules = append(rules, Rule{
+			Field:       column.Name,
+			Condition:   "utf8.RuneCountInString(" + getter + ") > " + strconv.Itoa(column.Length),
+			Description: "must be at most " + strconv.Itoa(column.Length) + " characters",
+		})
+	}
+
+	for _, check := range d.Table.Checks {
+		condition, ok := d.checkCondition(check.Expression, column, getter)
+		if !ok {
+			continue
+		}
+		if optional {
+			condition = "req." + SnakeToPascal(column.Name) + " != nil && " + condition
+		}
+		rules = append(rules, Rule{Field: column.Name, Condition: condition, Description: "must satisfy " + check.Expression})
+	}
+
+	return rules
+}
+
+// checkCondition translates a CHECK constraint on the column alone into
+// the Go expression that is true for an invalid value. Comparisons with
+// literals, BETWEEN, IN lists and their conjunctions are translated, other
+// checks are left to the database.
+func (d TemplateData) checkCondition(check string, column *schema.Column, getter string) (string, bool) {
+	check = trimParens(check)
+	if match := checkBetween.FindStringSubmatch(check); match != nil {
+		check = match[1] + " >= " + match[2] + " AND " + match[1] + " <= " + match[3]
+	}
+
+	var terms []string
+	for _, part := range checkAnd.Split(check, -1) {
+		term, ok := d.checkTerm(trimParens(part), column, getter)
+		if !ok {
+			return "", false
+		}
+		terms = append(terms, term)
+	}
+
+	if len(terms) == 1 && strings.HasPrefix(terms[0], "(") {
+		return "!" + terms[0], true
+	}
+	return "!(" + strings.Join(terms, " && ") + ")", true
+}
+
+// checkTerm translates a single comparison or IN list of the column.
+func (d TemplateData) checkTerm(term string, column *schema.Column, getter string) (string, bool) {
+	if match := checkIn.FindStringSubmatch(term); match != nil {
+		if !isColumn(match[1], column) {
+			return "", false
+		}
+
+		var values []string
+		for _, literal := range strings.Split(match[2], ",") {
+			value, ok := d.checkLiteral(strings.TrimSpace(literal), column)
+			if !ok {
+				return "", false
+			}
+			values = append(values, getter+" == "+value)
+		}
+		return "(" + strings.Join(values, " || ") + ")", true
+	}
+
+	var match = checkCompare.FindStringSubmatch(term)
+	if match == nil {
+		return "", false
+	}
+
+	var operator = match[2]
+	switch operator {
+	case "=":
+		operator = "=="
+	case "<>":
+		operator = "!="
+	}
+
+	if length := checkLength.FindStringSubmatch(match[1]); length != nil {
+		var number = checkNumber.FindStringSubmatch(match[3])
+		if !isColumn(length[1], column) || d.GoType(column) != "string" || column.Array || number == nil || number[1] != "" {
+			return "", false
+		}
+		return "utf8.RuneCountInString(" + getter + ") " + operator + " " + match[3], true
+	}
+
+	if !isColumn(match[1], column) {
+		return "", false
+	}
+	value, ok := d.checkLiteral(match[3], column)
+	if !ok {
+		return "", false
+	}
+	return getter + " " + operator + " " + value, true
+}
+
+// checkLiteral returns the Go constant of an SQL literal compared with the
+// column, numbers for numeric fields and strings for text fields.
+func (d TemplateData) checkLiteral(literal string, column *schema.Column) (string, bool) {
+	literal = checkCast.ReplaceAllString(literal, "")
+	if column.Array || d.EnumOf(column) != nil {
+		return "", false
+	}
+
+	switch goType := d.GoType(column); goType {
+	case "string":
+		var match = checkString.FindStringSubmatch(literal)
+		if match == nil || !plainText[column.Type] {
+			return "", false
+		}
+		return strconv.Quote(strings.ReplaceAll(match[1], "''", "'")), true
+	case "int32", "int64", "uint32", "uint64", "float32", "float64":
+		var match = checkNumber.FindStringSubmatch(literal)
+		if match == nil {
+			return "", false
+		}
+		if strings.HasPrefix(goType, "int") && match[1] != "" || strings.HasPrefix(goType, "uint") && (match[1] != "" || literal[0] == '-') {
+			return "", false
+		}
+		return literal, true
+	}
+	return "", false
+}
+
+func isColumn(s string, column *schema.Column) bool {
+	var match = checkIdentifier.FindStringSubmatch(strings.TrimSpace(s))
+	return match != nil && strings.ToLower(match[1]) == column.Name
+}
+
+// trimParens strips the parentheses enclosing the whole expression.
+func trimParens(s string) string {
+	s = strings.TrimSpace(s)
+	for strings.HasPrefix(s, "(") && strings.HasSuffix(s, ")") {
+		var depth int
+		for i, r := range s {
+			switch r {
+			case '(':
+				depth++
+			case ')':
+				depth--
+			}
+			if depth == 0 && i < len(s)-1 {
+				return s
+			}
+		}
+		s = strings.TrimSpace(s[1 : len(s)-1])
+	}
+	return s
+}
@@ -0,0 +1,85 @@
+package helper
+
+import (
+	"reflect"
+	"testing"
+
+	"githubc.com/asadbekGo/generate-code/config"
+)
+
+func TestPatchFields(t *testing.T) {
+	var tests = []struct {
+		name   string
+		column string
+		want   PatchField
+	}{
+		{
+			name:   "required text",
+			column: "name VARCHAR(10) NOT NULL",
+			want: PatchField{Field: "name", Type: "string", Kind: "a string", Rules: []Rule{
+				{Field: "name", Condition: "utf8.RuneCountInString(value) > 10", Description: "must be at most 10 characters"},
+			}},
+		},
+		{
+			name:   "uuid",
+			column: "owner_id UUID NOT NULL",
+			want: PatchField{Field: "owner_id", Type: "string", Kind: "a string", Rules: []Rule{
+				{Field: "owner_id", Condition: `value == ""`, Description: "is required"},
+				{Field: "owner_id", Init: "_, err := uuid.Parse(value)", Condition: `value != "" && err != nil`, Description: "must be a valid uuid"},
+			}},
+		},
+		{
+			name:   "integer with a check",
+			column: "age INT CHECK (age > 0)",
+			want: PatchField{Field: "age", Nullable: true, Type: "float64", Kind: "a number", Rules: []Rule{
+				{Field: "age", Condition: "value != float64(int64(value))", Description: "must be an integer"},
+				{Field: "age", Condition: "!(value > 0)", Description: "must satisfy age > 0"},
+			}},
+		},
+		{
+			name:   "enum",
+			column: "status status NOT NULL",
+			want: PatchField{Field: "status", Type: "string", Kind: "a string", Rules: []Rule{
+				{Field: "status", Condition: `!(value == "new" || value == "done")`, Description: "must be one of new, done"},
+			}},
+		},
+		{
+			name:   "jsonb",
+			column: "data JSONB NOT NULL",
+			want:   PatchField{Field: "data", Type: "map[string]interface{}", Kind: "an object"},
+		},
+		{
+			name:   "array",
+			column: "tags TEXT[]",
+			want:   PatchField{Field: "tags", Nullable: true, Type: "[]interface{}", Kind: "a list"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var data = parseTable(t, config.DefaultProject(), "CREATE TYPE status AS ENUM ('new', 'done'); CREATE TABLE t (id UUID PRIMARY KEY, "+test.column+");")
+
+			var fields = data.PatchFields()
+			if len(fields) != 1 {
+				t.Fatalf("got %d fields, want 1", len(fields))
+			}
+			if !reflect.DeepEqual(fields[0], test.want) {
+				t.Errorf("got %+v, want %+v", fields[0], test.want)
+			}
+		})
+	}
+}
+
+func TestUpdatePatchTemplate(t *testing.T) {
+	var data = parseTable(t, config.DefaultProject(), "CREATE TABLE t (id UUID PRIMARY KEY, name TEXT NOT NULL, note TEXT, count INT);")
+
+	containsAll(t, "storage/template_service.txt", render(t, data, "storage/template_service.txt"),
+		"err = validateUpdatePatchT(req)",
+		"fields     = req.GetFields().AsMap()",
+		"if _, err := uuid.Parse(req.GetId()); req.GetId() != \"\" && err != nil {",
+		"case \"name\":\n\t\t\tif value == nil {\n\t\t\t\tviolations = append(",
+		"case \"note\":\n\t\t\tif value == nil {\n\t\t\t\tcontinue\n\t\t\t}\n\t\t\t_, ok := value.(string)",
+		"value, ok := value.(float64)",
+		"Description: \"is not a field of t that can be patched\",",
+	)
+}
@@ -0,0 +1,347 @@
+package introspect
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"githubc.com/asadbekGo/generate-code/config"
+	"githubc.com/asadbekGo/generate-code/schema"
+)
+
+const (
+	enumsQuery = `
+		SELECT
+			t.typname::text,
+			e.enumlabel::text
+		FROM pg_type t
+		JOIN pg_enum e ON e.enumtypid = t.oid
+		JOIN pg_namespace n ON n.oid = t.typnamespace
+		WHERE n.nspname = $1
+		ORDER BY t.typname, e.enumsortorder
+	`
+
+	columnsQuery = `
+		SELECT
+			c.table_name::text,
+			c.column_name::text,
+			c.data_type::text,
+			c.udt_name::text,
+			COALESCE(c.character_maximum_length, 0)::int,
+			COALESCE(c.numeric_precision, 0)::int,
+			COALESCE(c.numeric_scale, 0)::int,
+			c.is_nullable::text = 'YES',
+			COALESCE(c.column_default::text, ''),
+			c.is_identity::text = 'YES',
+			c.is_generated::text = 'ALWAYS',
+			COALESCE(c.generation_expression::text, ''),
+			COALESCE(obj_description(cl.oid, 'pg_class'), ''),
+			COALESCE(col_description(cl.oid, c.ordinal_position::int), '')
+		FROM information_schema.columns c
+		JOIN information_schema.tables t ON t.table_schema = c.table_schema AND t.table_name = c.table_name
+		JOIN pg_namespace n ON n.nspname = c.table_schema
+		JOIN pg_class cl ON cl.relnamespace = n.oid AND cl.relname = c.table_name
+		WHERE c.table_schema = $1 AND t.table_type = 'BASE TABLE'
+		ORDER BY c.table_name, c.ordinal_position
+	`
+
+	constraintsQuery = `
+		SELECT
+			rel.relname::text,
+			con.conname::text,
+			con.contype::text,
+			ARRAY(
+				SELECT a.attname::text
+				FROM unnest(con.conkey) WITH ORDINALITY k(attnum, ord)
+				JOIN pg_attribute a ON a.attrelid = con.conrelid AND a.attnum = k.attnum
+				ORDER BY k.ord
+			),
+			COALESCE(frel.relname::text, ''),
+			ARRAY(
+				SELECT a.attname::text
+				FROM unnest(con.confkey) WITH ORDINALITY k(attnum, ord)
+				JOIN pg_attribute a ON a.attrelid = con.confrelid AND a.attnum = k.attnum
+				ORDER BY k.ord
+			),
+			con.confdeltype::text,
+			con.confupdtype::text,
+			CASE WHEN con.contype = 'c' THEN pg_get_constraintdef(con.oid) ELSE '' END
+		FROM pg_constraint con
+		JOIN pg_class rel ON rel.oid = con.conrelid
+		JOIN pg_namespace n ON n.oid = rel.relnamespace
+		LEFT JOIN pg_class frel ON frel.oid = con.confrelid
+		WHERE n.nspname = $1 AND con.contype IN ('p', 'u', 'f', 'c')
+		ORDER BY rel.relname, con.conname
+	`
+
+	indexesQuery = `
+		SELECT
+			t.relname::text,
+			i.relname::text,
+			ix.indisunique,
+			ARRAY(
+				SELECT a.attname::text
+				FROM unnest(ix.indkey::int2[]) WITH ORDINALITY k(attnum, ord)
+				JOIN pg_attribute a ON a.attrelid = ix.indrelid AND a.attnum = k.attnum
+				ORDER BY k.ord
+			),
+			ix.indexprs IS NOT NULL,
+			COALESCE(pg_get_expr(ix.indpred, ix.indrelid), '')
+		FROM pg_index ix
+		JOIN pg_class i ON i.oid = ix.indexrelid
+		JOIN pg_class t ON t.oid = ix.indrelid
+		JOIN pg_namespace n ON n.oid = t.relnamespace
+		WHERE n.nspname = $1
+			AND NOT ix.indisprimary
+			AND NOT EXISTS (SELECT 1 FROM pg_constraint c WHERE c.conindid = ix.indexrelid)
+		ORDER BY t.relname, i.relname
+	`
+)
+
+// referentialActions maps pg_constraint.confdeltype/confupdtype to SQL.
+var referentialActions = map[string]string{
+	"c": "CASCADE",
+	"r": "RESTRICT",
+	"n": "SET NULL",
+	"d": "SET DEFAULT",
+}
+
+// serialTypes are the pseudo types an integer column with a sequence
+// default was declared with.
+var serialTypes = map[string]string{
+	"smallint": "smallserial",
+	"integer":  "serial",
+	"bigint":   "bigserial",
+}
+
+func Connect(ctx context.Context, cfg config.Config) (*pgxpool.Pool, error) {
+	var dsn = url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(cfg.PostgresUser, cfg.PostgresPassword),
+		Host:     fmt.Sprintf("%s:%d", cfg.PostgresHost, cfg.PostgresPort),
+		Path:     cfg.PostgresDatabase,
+		RawQuery: "sslmode=disable",
+	}
+
+	poolConfig, err := pgxpool.ParseConfig(dsn.String())
+	if err != nil {
+		return nil, err
+	}
+
+	poolConfig.MaxConns = cfg.PostgresMaxConnections
+
+	return pgxpool.ConnectConfig(ctx, poolConfig)
+}
+
+// Load reads the tables, columns, constraints, indexes, enum types and
+// comments of a database schema, e.g. "public".
+func Load(ctx context.Context, db *pgxpool.Pool, schemaName string) (*schema.Schema, error) {
+	var s = &schema.Schema{}
+
+	err := loadEnums(ctx, db, schemaName, s)
+	if err != nil {
+		return nil, fmt.Errorf("enums: %w", err)
+	}
+
+	err = loadColumns(ctx, db, schemaName, s)
+	if err != nil {
+		return nil, fmt.Errorf("columns: %w", err)
+	}
+
+	err = loadConstraints(ctx, db, schemaName, s)
+	if err != nil {
+		return nil, fmt.Errorf("constraints: %w", err)
+	}
+
+	err = loadIndexes(ctx, db, schemaName, s)
+	if err != nil {
+		return nil, fmt.Errorf("indexes: %w", err)
+	}
+
+	for _, table := range s.Tables {
+		if err := table.Resolve(); err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+func loadEnums(ctx context.Context, db *pgxpool.Pool, schemaName string, s *schema.Schema) error {
+	rows, err := db.Query(ctx, enumsQuery, schemaName)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var name, value string
+		if err := rows.Scan(&name, &value); err != nil {
+			return err
+		}
+
+		var enum = s.Enum(name)
+		if enum == nil {
+			enum = &schema.Enum{Name: name}
+			s.Enums = append(s.Enums, enum)
+		}
+		enum.Values = append(enum.Values, value)
+	}
+
+	return rows.Err()
+}
+
+func loadColumns(ctx context.Context, db *pgxpool.Pool, schemaName string, s *schema.Schema) error {
+	rows, err := db.Query(ctx, columnsQuery, schemaName)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			tableName, dataType, udtName, generation, tableComment string
+			nullable                                               bool
+			column                                                 = &schema.Column{}
+		)
+
+		err := rows.Scan(
+			&tableName,
+			&column.Name,
+			&dataType,
+			&udtName,
+			&column.Length,
+			&column.Precision,
+			&column.Scale,
+			&nullable,
+			&column.Default,
+			&column.Identity,
+			&column.Generated,
+			&generation,
+			&tableComment,
+			&column.Comment,
+		)
+		if err != nil {
+			return err
+		}
+
+		switch dataType {
+		case "ARRAY":
+			column.Array = true
+			column.Type = schema.NormalizeType(strings.TrimPrefix(udtName, "_"))
+		case "USER-DEFINED":
+			column.Type = udtName
+		default:
+			column.Type = schema.NormalizeType(dataType)
+		}
+
+		// information_schema reports a precision for every numeric type,
+		// only the declared one of numeric(p,s) is meaningful
+		if column.Type != "numeric" {
+			column.Precision, column.Scale = 0, 0
+		}
+
+		if serial, ok := serialTypes[column.Type]; ok && strings.HasPrefix(column.Default, "nextval(") {
+			column.Type = serial
+		}
+
+		// a stored DEFAULT NULL reads back as NULL::type, like the parser
+		// it is the same as no default
+		if column.Default == "NULL" || strings.HasPrefix(column.Default, "NULL::") {
+			column.Default = ""
+		}
+
+		if column.Generated {
+			column.Default = generation
+		}
+
+		column.NotNull = !nullable
+		column.HasDefault = column.Default != "" || column.Identity
+
+		var table = s.Table(tableName)
+		if table == nil {
+			table = &schema.Table{Name: tableName, Comment: tableComment}
+			s.Tables = append(s.Tables, table)
+		}
+		table.Columns = append(table.Columns, column)
+	}
+
+	return rows.Err()
+}
+
+func loadConstraints(ctx context.Context, db *pgxpool.Pool, schemaName string, s *schema.Schema) error {
+	rows, err := db.Query(ctx, constraintsQuery, schemaName)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			tableName, name, kind, refTable, onDelete, onUpdate, definition string
+			columns, refColumns                                             []string
+		)
+
+		err := rows.Scan(&tableName, &name, &kind, &columns, &refTable, &refColumns, &onDelete, &onUpdate, &definition)
+		if err != nil {
+			return err
+		}
+
+		var table = s.Table(tableName)
+		if table == nil {
+			continue
+		}
+
+		switch kind {
+		case "p":
+			table.PrimaryKey, table.PrimaryKeyName = columns, name
+		case "u":
+			table.Indexes = append(table.Indexes, &schema.Index{Name: name, Columns: columns, Unique: true})
+		case "f":
+			table.ForeignKeys = append(table.ForeignKeys, &schema.ForeignKey{
+				Name:       name,
+				Columns:    columns,
+				RefTable:   refTable,
+				RefColumns: refColumns,
+				OnDelete:   referentialActions[onDelete],
+				OnUpdate:   referentialActions[onUpdate],
+			})
+		case "c":
+			definition = strings.TrimSuffix(strings.TrimPrefix(definition, "CHECK ("), ")")
+			table.Checks = append(table.Checks, &schema.Check{Name: name, Expression: definition})
+		}
+	}
+
+	return rows.Err()
+}
+
+func loadIndexes(ctx context.Context, db *pgxpool.Pool, schemaName string, s *schema.Schema) error {
+	rows, err := db.Query(ctx, indexesQuery, schemaName)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			tableName, name, predicate string
+			unique, expression         bool
+			columns                    []string
+		)
+
+		// expression columns have attnum 0 and are left out of columns
+		err := rows.Scan(&tableName, &name, &unique, &columns, &expression, &predicate)
+		if err != nil {
+			return err
+		}
+
+		if table := s.Table(tableName); table != nil {
+			table.Indexes = append(table.Indexes, &schema.Index{Name: name, Columns: columns, Unique: unique, Expression: expression, Predicate: predicate})
+		}
+	}
+
+	return rows.Err()
+}
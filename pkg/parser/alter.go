@@ -0,0 +1,419 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	"githubc.com/asadbekGo/generate-code/schema"
+)
+
+// tableRef reads the name of an existing table, the error points at the
+// name when the table is unknown.
+func (p *parser) tableRef() (*schema.Table, error) {
+	var tok = p.peek()
+	name, err := p.parseName()
+	if err != nil {
+		return nil, err
+	}
+
+	var table = p.schema.Table(name)
+	if table == nil {
+		return nil, &Error{File: p.file, Line: tok.line, Column: tok.column, Msg: fmt.Sprintf("table %q does not exist", name)}
+	}
+
+	return table, nil
+}
+
+func (p *parser) parseString() (string, error) {
+	var tok = p.peek()
+	if tok.kind != tokenString {
+		return "", p.errorf("expected string literal, found %s", tok)
+	}
+	p.next()
+
+	return tok.text, nil
+}
+
+func (p *parser) parseCreateType() error {
+	p.expect("CREATE", "TYPE")
+
+	name, err := p.parseName()
+	if err != nil {
+		return err
+	}
+
+	if !p.accept("AS", "ENUM") {
+		// composite, range and base types do not map to columns we generate
+		p.skipStatement()
+		return nil
+	}
+
+	var enum = &schema.Enum{Name: name}
+	if err := p.expect("("); err != nil {
+		return err
+	}
+	for !p.peekIs(")") {
+		value, err := p.parseString()
+		if err != nil {
+			return err
+		}
+		enum.Values = append(enum.Values, value)
+
+		if !p.accept(",") {
+			break
+		}
+	}
+	if err := p.expect(")"); err != nil {
+		return err
+	}
+
+	p.schema.DropEnum(name)
+	p.schema.Enums = append(p.schema.Enums, enum)
+	return nil
+}
+
+func (p *parser) parseAlterType() error {
+	p.expect("ALTER", "TYPE")
+
+	var tok = p.peek()
+	name, err := p.parseName()
+	if err != nil {
+		return err
+	}
+
+	var enum = p.schema.Enum(name)
+	if enum == nil {
+		p.skipStatement()
+		return nil
+	}
+
+	switch {
+	case p.accept("ADD", "VALUE"):
+		var ifNotExists = p.accept("IF", "NOT", "EXISTS")
+		value, err := p.parseString()
+		if err != nil {
+			return err
+		}
+		if contains(enum.Values, value) {
+			if ifNotExists {
+				break
+			}
+			return &Error{File: p.file, Line: tok.line, Column: tok.column, Msg: fmt.Sprintf("enum %s already has value %q", name, value)}
+		}
+
+		var position = len(enum.Values)
+		if before := p.accept("BEFORE"); before || p.accept("AFTER") {
+			other, err := p.parseString()
+			if err != nil {
+				return err
+			}
+			position = indexOf(enum.Values, other)
+			if position < 0 {
+				return p.errorf("enum %s has no value %q", name, other)
+			}
+			if !before {
+				position++
+			}
+		}
+
+		enum.Values = append(enum.Values[:position], append([]string{value}, enum.Values[position:]...)...)
+
+	case p.accept("RENAME", "VALUE"):
+		from, err := p.parseString()
+		if err != nil {
+			return err
+		}
+		if err := p.expect("TO"); err != nil {
+			return err
+		}
+		to, err := p.parseString()
+		if err != nil {
+			return err
+		}
+		if i := indexOf(enum.Values, from); i >= 0 {
+			enum.Values[i] = to
+		}
+
+	case p.accept("RENAME", "TO"):
+		to, err := p.parseIdent()
+		if err != nil {
+			return err
+		}
+		for _, table := range p.schema.Tables {
+			for _, column := range table.Columns {
+				if column.Type == enum.Name {
+					column.Type = to
+				}
+			}
+		}
+		enum.Name = to
+	}
+
+	p.skipStatement()
+	return nil
+}
+
+func (p *parser) parseDrop() error {
+	p.expect("DROP")
+
+	var kind = strings.ToUpper(p.next().text)
+	p.accept("CONCURRENTLY")
+	var ifExists = p.accept("IF", "EXISTS")
+
+	for {
+		var tok = p.peek()
+		name, err := p.parseName()
+		if err != nil {
+			return err
+		}
+
+		switch kind {
+		case "TABLE":
+			if p.schema.Table(name) == nil && !ifExists {
+				return &Error{File: p.file, Line: tok.line, Column: tok.column, Msg: fmt.Sprintf("table %q does not exist", name)}
+			}
+			p.schema.DropTable(name)
+		case "INDEX":
+			if table := p.schema.DropIndex(name); table != nil {
+				if err := table.Resolve(); err != nil {
+					return err
+				}
+			}
+		case "TYPE":
+			p.schema.DropEnum(name)
+		}
+
+		if !p.accept(",") {
+			break
+		}
+	}
+
+	p.skipStatement()
+	return nil
+}
+
+func (p *parser) parseAlterTable() error {
+	p.expect("ALTER", "TABLE")
+	var ifExists = p.accept("IF", "EXISTS")
+	p.accept("ONLY")
+
+	if ifExists {
+		var save = p.pos
+		name, err := p.parseName()
+		if err != nil {
+			return err
+		}
+		if p.schema.Table(name) == nil {
+			p.skipStatement()
+			return nil
+		}
+		p.pos = save
+	}
+
+	table, err := p.tableRef()
+	if err != nil {
+		return err
+	}
+
+	if p.accept("RENAME", "TO") {
+		name, err := p.parseIdent()
+		if err != nil {
+			return err
+		}
+		p.schema.RenameTable(table.Name, name)
+		p.skipStatement()
+		return nil
+	}
+
+	for {
+		if err := p.parseAlterAction(table); err != nil {
+			return err
+		}
+		if !p.accept(",") {
+			break
+		}
+	}
+
+	if !p.at(tokenEOF) && !p.peekIs(";") {
+		return p.errorf("expected \",\" or \";\", found %s", p.peek())
+	}
+
+	return table.Resolve()
+}
+
+func (p *parser) parseAlterAction(table *schema.Table) error {
+	switch {
+	case p.accept("ADD"):
+		if p.peekIs("CONSTRAINT") || p.peekIs("PRIMARY") || p.peekIs("UNIQUE") ||
+			p.peekIs("FOREIGN") || p.peekIs("CHECK") || p.peekIs("EXCLUDE") {
+			return p.parseTableElement(table)
+		}
+
+		p.accept("COLUMN")
+		if p.accept("IF", "NOT", "EXISTS") {
+			var save = p.pos
+			name, err := p.parseIdent()
+			if err != nil {
+				return err
+			}
+			if table.HasColumn(name) {
+				p.skipBalanced(func(token) bool { return false })
+				return nil
+			}
+			p.pos = save
+		}
+		return p.parseColumn(table)
+
+	case p.accept("DROP", "CONSTRAINT"):
+		var ifExists = p.accept("IF", "EXISTS")
+		var tok = p.peek()
+		name, err := p.parseIdent()
+		if err != nil {
+			return err
+		}
+		if !table.DropConstraint(name) && !ifExists {
+			return &Error{File: p.file, Line: tok.line, Column: tok.column, Msg: fmt.Sprintf("constraint %q of table %s does not exist", name, table.Name)}
+		}
+		p.accept("CASCADE")
+		p.accept("RESTRICT")
+
+	case p.accept("DROP"):
+		p.accept("COLUMN")
+		var ifExists = p.accept("IF", "EXISTS")
+		var tok = p.peek()
+		name, err := p.parseIdent()
+		if err != nil {
+			return err
+		}
+		if !table.HasColumn(name) && !ifExists {
+			return &Error{File: p.file, Line: tok.line, Column: tok.column, Msg: fmt.Sprintf("column %q of table %s does not exist", name, table.Name)}
+		}
+		table.DropColumn(name)
+		p.accept("CASCADE")
+		p.accept("RESTRICT")
+
+	case p.accept("RENAME", "CONSTRAINT"):
+		from, err := p.parseIdent()
+		if err != nil {
+			return err
+		}
+		if err := p.expect("TO"); err != nil {
+			return err
+		}
+		to, err := p.parseIdent()
+		if err != nil {
+			return err
+		}
+		table.RenameConstraint(from, to)
+
+	case p.accept("RENAME"):
+		p.accept("COLUMN")
+		var tok = p.peek()
+		from, err := p.parseIdent()
+		if err != nil {
+			return err
+		}
+		if err := p.expect("TO"); err != nil {
+			return err
+		}
+		to, err := p.parseIdent()
+		if err != nil {
+			return err
+		}
+		if !table.HasColumn(from) {
+			return &Error{File: p.file, Line: tok.line, Column: tok.column, Msg: fmt.Sprintf("column %q of table %s does not exist", from, table.Name)}
+		}
+		p.schema.RenameColumn(table, from, to)
+
+	case p.accept("ALTER"):
+		p.accept("COLUMN")
+		var tok = p.peek()
+		name, err := p.parseIdent()
+		if err != nil {
+			return err
+		}
+		var column = table.Column(name)
+		if column == nil {
+			return &Error{File: p.file, Line: tok.line, Column: tok.column, Msg: fmt.Sprintf("column %q of table %s does not exist", name, table.Name)}
+		}
+		return p.parseAlterColumn(column)
+
+	default:
+		// OWNER TO, SET SCHEMA, ENABLE TRIGGER and the like
+		p.skipBalanced(func(token) bool { return false })
+	}
+
+	return nil
+}
+
+func (p *parser) parseAlterColumn(column *schema.Column) error {
+	switch {
+	case p.accept("SET", "DATA", "TYPE"), p.accept("TYPE"):
+		var changed = &schema.Column{Name: column.Name}
+		if err := p.parseType(changed); err != nil {
+			return err
+		}
+		column.Type = changed.Type
+		column.Length = changed.Length
+		column.Precision = changed.Precision
+		column.Scale = changed.Scale
+		column.Array = changed.Array
+
+		if p.accept("COLLATE") {
+			if _, err := p.parseName(); err != nil {
+				return err
+			}
+		}
+		if p.accept("USING") {
+			p.skipBalanced(func(token) bool { return false })
+		}
+
+	case p.accept("SET", "NOT", "NULL"):
+		column.NotNull = true
+
+	case p.accept("DROP", "NOT", "NULL"):
+		column.NotNull = false
+
+	case p.accept("SET", "DEFAULT", "NULL"):
+		p.skipBalanced(func(token) bool { return false })
+		column.HasDefault = column.Identity
+		column.Default = ""
+
+	case p.accept("SET", "DEFAULT"):
+		column.HasDefault = true
+		column.Default = p.skipBalanced(func(token) bool { return false })
+
+	case p.accept("DROP", "DEFAULT"):
+		column.HasDefault = column.Identity
+		column.Default = ""
+
+	case p.accept("DROP", "IDENTITY"):
+		p.accept("IF", "EXISTS")
+		column.Identity = false
+		column.HasDefault = column.Default != ""
+
+	case p.accept("ADD", "GENERATED"):
+		column.Identity = true
+		column.HasDefault = true
+		p.skipBalanced(func(token) bool { return false })
+
+	default:
+		// SET STATISTICS, SET STORAGE and the like
+		p.skipBalanced(func(token) bool { return false })
+	}
+
+	return nil
+}
+
+func contains(s []string, e string) bool {
+	return indexOf(s, e) >= 0
+}
+
+func indexOf(s []string, e string) int {
+	for i, a := range s {
+		if a == e {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,270 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"githubc.com/asadbekGo/generate-code/schema"
+)
+
+const alterBase = `
+CREATE TABLE client (id UUID PRIMARY KEY);
+CREATE TABLE account (
+	id UUID NOT NULL,
+	tenant_id UUID NOT NULL,
+	client_id UUID REFERENCES client(id),
+	email TEXT NOT NULL,
+	name TEXT DEFAULT 'none',
+	CONSTRAINT account_pkey PRIMARY KEY (id, tenant_id),
+	CONSTRAINT account_email_key UNIQUE (email)
+);
+CREATE UNIQUE INDEX account_name_idx ON account (name);
+`
+
+func TestParseAlter(t *testing.T) {
+	var tests = []struct {
+		name  string
+		table string
+		alter string
+		check func(t *testing.T, table *schema.Table)
+	}{
+		{
+			name:  "drop primary key",
+			alter: "ALTER TABLE account DROP CONSTRAINT account_pkey;",
+			check: func(t *testing.T, table *schema.Table) {
+				if len(table.PrimaryKey) != 0 || table.Column("id").PrimaryKey || table.Column("tenant_id").PrimaryKey {
+					t.Errorf("primary key was not dropped: %v", table.PrimaryKey)
+				}
+			},
+		},
+		{
+			name:  "drop named primary key",
+			table: "item",
+			alter: "CREATE TABLE item (id UUID, CONSTRAINT pk_item PRIMARY KEY (id)); ALTER TABLE item DROP CONSTRAINT pk_item;",
+			check: func(t *testing.T, table *schema.Table) {
+				if len(table.PrimaryKey) != 0 || table.Column("id").PrimaryKey {
+					t.Errorf("primary key was not dropped: %v", table.PrimaryKey)
+				}
+			},
+		},
+		{
+			name:  "drop renamed primary key",
+			alter: "ALTER TABLE account RENAME CONSTRAINT account_pkey TO account_key; ALTER TABLE account DROP CONSTRAINT account_key;",
+			check: func(t *testing.T, table *schema.Table) {
+				if len(table.PrimaryKey) != 0 {
+					t.Errorf("primary key was not dropped: %v", table.PrimaryKey)
+				}
+			},
+		},
+		{
+			name:  "drop named check",
+			alter: "ALTER TABLE account ADD COLUMN age INT, ADD CONSTRAINT age_positive CHECK (age > 0); ALTER TABLE account DROP CONSTRAINT age_positive;",
+			check: func(t *testing.T, table *schema.Table) {
+				if len(table.Checks) != 0 {
+					t.Errorf("check was not dropped: %+v", table.Checks)
+				}
+			},
+		},
+		{
+			name:  "drop default named checks",
+			alter: "ALTER TABLE account ADD COLUMN age INT CHECK (age > 0), ADD CHECK (age < 200), ADD CHECK (age < length(name)); ALTER TABLE account DROP CONSTRAINT account_age_check1;",
+			check: func(t *testing.T, table *schema.Table) {
+				var want = []schema.Check{
+					{Name: "account_age_check", Expression: "age > 0"},
+					{Name: "account_check", Expression: "age < length(name)"},
+				}
+				if len(table.Checks) != len(want) {
+					t.Fatalf("checks = %+v, want %+v", table.Checks, want)
+				}
+				for i, check := range table.Checks {
+					if *check != want[i] {
+						t.Errorf("check %d = %+v, want %+v", i, *check, want[i])
+					}
+				}
+			},
+		},
+		{
+			name:  "drop primary key column",
+			alter: "ALTER TABLE account DROP COLUMN tenant_id;",
+			check: func(t *testing.T, table *schema.Table) {
+				if len(table.PrimaryKey) != 0 || table.Column("id").PrimaryKey {
+					t.Errorf("primary key was not dropped: %v", table.PrimaryKey)
+				}
+			},
+		},
+		{
+			name:  "drop unique constraint",
+			alter: "ALTER TABLE account DROP CONSTRAINT account_email_key;",
+			check: func(t *testing.T, table *schema.Table) {
+				if table.Column("email").Unique {
+					t.Error("email is still unique")
+				}
+			},
+		},
+		{
+			name:  "drop unique index",
+			alter: "DROP INDEX account_name_idx;",
+			check: func(t *testing.T, table *schema.Table) {
+				if table.Column("name").Unique {
+					t.Error("name is still unique")
+				}
+			},
+		},
+		{
+			name:  "drop one of two unique indexes",
+			alter: "CREATE UNIQUE INDEX account_email_idx ON account (email); ALTER TABLE account DROP CONSTRAINT account_email_key;",
+			check: func(t *testing.T, table *schema.Table) {
+				if !table.Column("email").Unique {
+					t.Error("email is no longer unique")
+				}
+			},
+		},
+		{
+			name:  "drop foreign key",
+			alter: "ALTER TABLE account DROP CONSTRAINT account_client_id_fkey;",
+			check: func(t *testing.T, table *schema.Table) {
+				if len(table.ForeignKeys) != 0 || table.Column("client_id").References != nil {
+					t.Errorf("foreign key was not dropped: %v", table.ForeignKeys)
+				}
+			},
+		},
+		{
+			name:  "drop referenced table",
+			alter: "DROP TABLE client;",
+			check: func(t *testing.T, table *schema.Table) {
+				if len(table.ForeignKeys) != 0 || table.Column("client_id").References != nil {
+					t.Errorf("foreign key was not dropped: %v", table.ForeignKeys)
+				}
+			},
+		},
+		{
+			name:  "add primary key",
+			alter: "ALTER TABLE account DROP CONSTRAINT account_pkey, ADD PRIMARY KEY (email);",
+			check: func(t *testing.T, table *schema.Table) {
+				if !reflect.DeepEqual(table.PrimaryKey, []string{"email"}) || !table.Column("email").PrimaryKey || table.Column("id").PrimaryKey {
+					t.Errorf("primary key = %v", table.PrimaryKey)
+				}
+			},
+		},
+		{
+			name:  "rename column",
+			alter: "ALTER TABLE account RENAME COLUMN email TO login;",
+			check: func(t *testing.T, table *schema.Table) {
+				if table.HasColumn("email") || !table.Column("login").Unique {
+					t.Error("email was not renamed to a unique login")
+				}
+				if index := table.Indexes[0]; !reflect.DeepEqual(index.Columns, []string{"login"}) {
+					t.Errorf("index columns = %v", index.Columns)
+				}
+			},
+		},
+		{
+			name:  "alter column",
+			alter: "ALTER TABLE account ALTER COLUMN email DROP NOT NULL, ALTER COLUMN email TYPE VARCHAR(64), ALTER COLUMN name SET DEFAULT NULL;",
+			check: func(t *testing.T, table *schema.Table) {
+				var email = table.Column("email")
+				if email.NotNull || email.Type != "varchar" || email.Length != 64 {
+					t.Errorf("email = %+v", email)
+				}
+				if name := table.Column("name"); name.HasDefault || name.Default != "" {
+					t.Errorf("name default = %q", name.Default)
+				}
+			},
+		},
+		{
+			name:  "add column",
+			alter: "ALTER TABLE account ADD COLUMN IF NOT EXISTS email TEXT, ADD COLUMN age INT NOT NULL DEFAULT 0;",
+			check: func(t *testing.T, table *schema.Table) {
+				if len(table.Columns) != 6 || !table.Column("age").HasDefault {
+					t.Errorf("got %d columns", len(table.Columns))
+				}
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s, err := Parse("test.sql", alterBase+test.alter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if test.table == "" {
+				test.table = "account"
+			}
+			test.check(t, s.Table(test.table))
+		})
+	}
+}
+
+func TestParseAlterErrors(t *testing.T) {
+	var tests = []struct {
+		alter string
+		want  string
+	}{
+		{"ALTER TABLE account DROP COLUMN age;", `column "age" of table account does not exist`},
+		{"ALTER TABLE account DROP CONSTRAINT account_age_key;", `constraint "account_age_key" of table account does not exist`},
+		{"ALTER TABLE account RENAME COLUMN age TO years;", `column "age" of table account does not exist`},
+		{"DROP TABLE invoice;", `table "invoice" does not exist`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.alter, func(t *testing.T) {
+			_, err := Parse("test.sql", alterBase+test.alter)
+			parseErr, ok := err.(*Error)
+			if !ok {
+				t.Fatalf("got %v, want a parser error", err)
+			}
+			if parseErr.Msg != test.want {
+				t.Errorf("got %q, want %q", parseErr.Msg, test.want)
+			}
+		})
+	}
+
+	for _, alter := range []string{
+		"ALTER TABLE account DROP COLUMN IF EXISTS age;",
+		"ALTER TABLE account DROP CONSTRAINT IF EXISTS account_age_key;",
+		"ALTER TABLE IF EXISTS invoice ADD COLUMN total INT;",
+		"DROP TABLE IF EXISTS invoice;",
+	} {
+		if _, err := Parse("test.sql", alterBase+alter); err != nil {
+			t.Errorf("%s: %v", alter, err)
+		}
+	}
+}
+
+func TestParseMigrations(t *testing.T) {
+	var dir = t.TempDir()
+
+	var files = map[string]string{
+		"000001_init.up.sql":        alterBase,
+		"000001_init.down.sql":      "DROP TABLE account; DROP TABLE client;",
+		"000002_email.up.sql":       "ALTER TABLE account DROP CONSTRAINT account_email_key;",
+		"000010_tenant.up.sql":      "ALTER TABLE account DROP COLUMN tenant_id;",
+		"000003_drop_name.up.sql":   "ALTER TABLE account DROP COLUMN name;",
+		"000003_drop_name.down.sql": "ALTER TABLE account ADD COLUMN name TEXT;",
+	}
+	for name, body := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(body), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s, err := ParseMigrations(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var table = s.Table("account")
+	var names []string
+	for _, column := range table.Columns {
+		names = append(names, column.Name)
+	}
+	if want := []string{"id", "client_id", "email"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("columns = %v, want %v", names, want)
+	}
+	if len(table.PrimaryKey) != 0 || table.Column("id").PrimaryKey || table.Column("email").Unique {
+		t.Errorf("stale flags after migrations: %+v", table)
+	}
+}
@@ -0,0 +1,106 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// leadingComment returns the comment lines standing right above line.
+func (p *parser) leadingComment(line int) string {
+	var lines []string
+	for l := line - 1; !p.tokenLines[l]; l-- {
+		text, ok := p.comments[l]
+		if !ok {
+			break
+		}
+		lines = append([]string{text}, lines...)
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// trailingComment returns the comment at the end of the line of the last
+// read token, unless another element continues on that line.
+func (p *parser) trailingComment() string {
+	var (
+		line = p.tokens[p.pos-1].line
+		next = p.pos
+	)
+
+	if p.tokens[next].is(",") {
+		next++
+	}
+	if next < len(p.tokens) && p.tokens[next].kind != tokenEOF && p.tokens[next].line == line {
+		return ""
+	}
+
+	return p.comments[line]
+}
+
+// parseComment reads COMMENT ON TABLE and COMMENT ON COLUMN, other objects
+// are skipped. IS NULL removes the comment.
+func (p *parser) parseComment() error {
+	p.expect("COMMENT", "ON")
+
+	switch {
+	case p.accept("TABLE"):
+		table, err := p.tableRef()
+		if err != nil {
+			return err
+		}
+
+		comment, err := p.parseCommentText()
+		if err != nil {
+			return err
+		}
+		table.Comment = comment
+
+	case p.accept("COLUMN"):
+		var (
+			tok   = p.peek()
+			names []string
+		)
+		for {
+			name, err := p.parseIdent()
+			if err != nil {
+				return err
+			}
+			names = append(names, name)
+			if !p.accept(".") {
+				break
+			}
+		}
+		if len(names) < 2 {
+			return p.errorf("expected table.column, found %s", p.peek())
+		}
+
+		var table = p.schema.Table(names[len(names)-2])
+		if table == nil {
+			return &Error{File: p.file, Line: tok.line, Column: tok.column, Msg: fmt.Sprintf("table %q does not exist", names[len(names)-2])}
+		}
+		var column = table.Column(names[len(names)-1])
+		if column == nil {
+			return &Error{File: p.file, Line: tok.line, Column: tok.column, Msg: fmt.Sprintf("column %q of table %q does not exist", names[len(names)-1], table.Name)}
+		}
+
+		comment, err := p.parseCommentText()
+		if err != nil {
+			return err
+		}
+		column.Comment = comment
+	}
+
+	p.skipStatement()
+	return nil
+}
+
+func (p *parser) parseCommentText() (string, error) {
+	if err := p.expect("IS"); err != nil {
+		return "", err
+	}
+	if p.accept("NULL") {
+		return "", nil
+	}
+
+	comment, err := p.parseString()
+	return strings.TrimSpace(comment), err
+}
@@ -0,0 +1,243 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenQuotedIdent
+	tokenString
+	tokenNumber
+	tokenPunct
+)
+
+type token struct {
+	kind   tokenKind
+	text   string
+	start  int
+	end    int
+	line   int
+	column int
+}
+
+func (t token) String() string {
+	if t.kind == tokenEOF {
+		return "end of input"
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// is reports whether the token is the given keyword or punctuation,
+// keywords are compared case-insensitively.
+func (t token) is(text string) bool {
+	switch t.kind {
+	case tokenIdent:
+		return strings.EqualFold(t.text, text)
+	case tokenPunct:
+		return t.text == text
+	}
+	return false
+}
+
+type lexer struct {
+	file   string
+	src    string
+	pos    int
+	line   int
+	column int
+	// comments holds the text of the -- comments by line.
+	comments map[int]string
+}
+
+func tokenize(file, src string) ([]token, map[int]string, error) {
+	var (
+		l      = &lexer{file: file, src: src, line: 1, column: 1, comments: map[int]string{}}
+		tokens []token
+	)
+
+	for {
+		tok, err := l.next()
+		if err != nil {
+			return nil, nil, err
+		}
+		tokens = append(tokens, tok)
+		if tok.kind == tokenEOF {
+			return tokens, l.comments, nil
+		}
+	}
+}
+
+func (l *lexer) errorf(line, column int, format string, args ...interface{}) error {
+	return &Error{File: l.file, Line: line, Column: column, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (l *lexer) peekByte(offset int) byte {
+	if l.pos+offset < len(l.src) {
+		return l.src[l.pos+offset]
+	}
+	return 0
+}
+
+func (l *lexer) advance(n int) {
+	for i := 0; i < n && l.pos < len(l.src); i++ {
+		if l.src[l.pos] == '\n' {
+			l.line++
+			l.column = 1
+		} else {
+			l.column++
+		}
+		l.pos++
+	}
+}
+
+func (l *lexer) skipSpaceAndComments() error {
+	for l.pos < len(l.src) {
+		var c = l.src[l.pos]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f':
+			l.advance(1)
+		case c == '-' && l.peekByte(1) == '-':
+			var start, line = l.pos, l.line
+			for l.pos < len(l.src) && l.src[l.pos] != '\n' {
+				l.advance(1)
+			}
+			l.comments[line] = strings.TrimSpace(strings.TrimLeft(l.src[start:l.pos], "-"))
+		case c == '/' && l.peekByte(1) == '*':
+			var line, column = l.line, l.column
+			end := strings.Index(l.src[l.pos+2:], "*/")
+			if end < 0 {
+				return l.errorf(line, column, "unterminated block comment")
+			}
+			l.advance(end + 4)
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+func (l *lexer) next() (token, error) {
+	if err := l.skipSpaceAndComments(); err != nil {
+		return token{}, err
+	}
+
+	var tok = token{start: l.pos, line: l.line, column: l.column}
+	if l.pos >= len(l.src) {
+		tok.kind, tok.end = tokenEOF, l.pos
+		return tok, nil
+	}
+
+	var c = l.src[l.pos]
+	switch {
+	case c == '"':
+		tok.kind = tokenQuotedIdent
+		var text strings.Builder
+		l.advance(1)
+		for {
+			if l.pos >= len(l.src) {
+				return tok, l.errorf(tok.line, tok.column, "unterminated quoted identifier")
+			}
+			if l.src[l.pos] == '"' {
+				if l.peekByte(1) == '"' {
+					text.WriteByte('"')
+					l.advance(2)
+					continue
+				}
+				l.advance(1)
+				break
+			}
+			text.WriteByte(l.src[l.pos])
+			l.advance(1)
+		}
+		tok.text = text.String()
+
+	case c == '\'' || ((c == 'E' || c == 'e') && l.peekByte(1) == '\''):
+		tok.kind = tokenString
+		if c != '\'' {
+			l.advance(1)
+		}
+		var text strings.Builder
+		l.advance(1)
+		for {
+			if l.pos >= len(l.src) {
+				return tok, l.errorf(tok.line, tok.column, "unterminated string literal")
+			}
+			if l.src[l.pos] == '\\' && c != '\'' && l.pos+1 < len(l.src) {
+				text.WriteByte(l.src[l.pos+1])
+				l.advance(2)
+				continue
+			}
+			if l.src[l.pos] == '\'' {
+				if l.peekByte(1) == '\'' {
+					text.WriteByte('\'')
+					l.advance(2)
+					continue
+				}
+				l.advance(1)
+				break
+			}
+			text.WriteByte(l.src[l.pos])
+			l.advance(1)
+		}
+		tok.text = text.String()
+
+	case c == '$' && (l.peekByte(1) == '$' || isIdentStart(rune(l.peekByte(1)))):
+		end := strings.IndexByte(l.src[l.pos+1:], '$')
+		if end < 0 {
+			return tok, l.errorf(tok.line, tok.column, "unterminated dollar quote")
+		}
+		var tag = l.src[l.pos : l.pos+end+2]
+		body := strings.Index(l.src[l.pos+len(tag):], tag)
+		if body < 0 {
+			return tok, l.errorf(tok.line, tok.column, "unterminated dollar quoted string %s", tag)
+		}
+		tok.kind = tokenString
+		tok.text = l.src[l.pos+len(tag) : l.pos+len(tag)+body]
+		l.advance(len(tag)*2 + body)
+
+	case isIdentStart(rune(c)):
+		tok.kind = tokenIdent
+		for l.pos < len(l.src) && isIdentPart(rune(l.src[l.pos])) {
+			l.advance(1)
+		}
+		tok.text = l.src[tok.start:l.pos]
+
+	case c >= '0' && c <= '9' || c == '.' && l.peekByte(1) >= '0' && l.peekByte(1) <= '9':
+		tok.kind = tokenNumber
+		for l.pos < len(l.src) && (l.src[l.pos] >= '0' && l.src[l.pos] <= '9' || l.src[l.pos] == '.' ||
+			l.src[l.pos] == 'e' || l.src[l.pos] == 'E') {
+			l.advance(1)
+		}
+		tok.text = l.src[tok.start:l.pos]
+
+	default:
+		tok.kind = tokenPunct
+		for _, op := range []string{"::", "<=", ">=", "<>", "!=", "||", "->>", "->", "=>"} {
+			if strings.HasPrefix(l.src[l.pos:], op) {
+				tok.text = op
+				break
+			}
+		}
+		if tok.text == "" {
+			tok.text = string(c)
+		}
+		l.advance(len(tok.text))
+	}
+
+	tok.end = l.pos
+	return tok, nil
+}
+
+func isIdentStart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || r >= 0x80
+}
+
+func isIdentPart(r rune) bool {
+	return isIdentStart(r) || unicode.IsDigit(r) || r == '$'
+}
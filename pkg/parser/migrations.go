@@ -0,0 +1,65 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"githubc.com/asadbekGo/generate-code/schema"
+)
+
+// ParseMigrations applies the up migrations of dir in order and returns
+// the resulting schema. Files are ordered by their numeric prefix, e.g.
+// 000012_add_currency.up.sql, and *.down.sql files are ignored.
+func ParseMigrations(dir string) (*schema.Schema, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, entry := range entries {
+		var name = entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".sql") || strings.HasSuffix(name, ".down.sql") {
+			continue
+		}
+		files = append(files, name)
+	}
+
+	sort.SliceStable(files, func(i, j int) bool {
+		vi, vj := migrationVersion(files[i]), migrationVersion(files[j])
+		if vi != vj {
+			return vi < vj
+		}
+		return files[i] < files[j]
+	})
+
+	var s = &schema.Schema{}
+	for _, name := range files {
+		var filename = filepath.Join(dir, name)
+
+		body, err := os.ReadFile(filename)
+		if err != nil {
+			return nil, err
+		}
+
+		err = ParseInto(s, filename, string(body))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+func migrationVersion(name string) uint64 {
+	var end = strings.IndexFunc(name, func(r rune) bool { return r < '0' || r > '9' })
+	if end < 0 {
+		end = len(name)
+	}
+
+	version, _ := strconv.ParseUint(name[:end], 10, 64)
+	return version
+}
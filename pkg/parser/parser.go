@@ -0,0 +1,769 @@
+package parser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"githubc.com/asadbekGo/generate-code/schema"
+)
+
+// Error is a syntax error at a position of the input.
+type Error struct {
+	File   string
+	Line   int
+	Column int
+	Msg    string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s:%d:%d: %s", e.File, e.Line, e.Column, e.Msg)
+}
+
+type parser struct {
+	file     string
+	src      string
+	tokens   []token
+	pos      int
+	schema   *schema.Schema
+	comments map[int]string
+	// tokenLines are the lines holding a token, a comment on any other line
+	// stands on its own.
+	tokenLines map[int]bool
+}
+
+// Parse reads the CREATE TABLE and CREATE INDEX statements of src into a
+// schema. Statements outside of that subset are skipped.
+func Parse(file, src string) (*schema.Schema, error) {
+	var s = &schema.Schema{}
+
+	err := ParseInto(s, file, src)
+	if err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// ParseInto applies the statements of src to an existing schema, so that
+// later files can alter the tables created by earlier ones.
+func ParseInto(s *schema.Schema, file, src string) error {
+	tokens, comments, err := tokenize(file, src)
+	if err != nil {
+		return err
+	}
+
+	var p = &parser{
+		file:       file,
+		src:        src,
+		tokens:     tokens,
+		schema:     s,
+		comments:   comments,
+		tokenLines: map[int]bool{},
+	}
+	for _, tok := range tokens {
+		p.tokenLines[tok.line] = true
+	}
+
+	return p.parse()
+}
+
+func (p *parser) parse() error {
+	for !p.at(tokenEOF) {
+		if p.accept(";") {
+			continue
+		}
+
+		var err error
+		switch {
+		case p.peekIs("CREATE", "TABLE"), p.peekIs("CREATE", "UNLOGGED", "TABLE"),
+			p.peekIs("CREATE", "TEMP", "TABLE"), p.peekIs("CREATE", "TEMPORARY", "TABLE"):
+			err = p.parseCreateTable()
+		case p.peekIs("CREATE", "INDEX"), p.peekIs("CREATE", "UNIQUE", "INDEX"):
+			err = p.parseCreateIndex()
+		case p.peekIs("CREATE", "TYPE"):
+			err = p.parseCreateType()
+		case p.peekIs("ALTER", "TABLE"):
+			err = p.parseAlterTable()
+		case p.peekIs("ALTER", "TYPE"):
+			err = p.parseAlterType()
+		case p.peekIs("DROP", "TABLE"), p.peekIs("DROP", "INDEX"), p.peekIs("DROP", "TYPE"):
+			err = p.parseDrop()
+		case p.peekIs("COMMENT", "ON"):
+			err = p.parseComment()
+		default:
+			p.skipStatement()
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) peekN(n int) token {
+	if p.pos+n < len(p.tokens) {
+		return p.tokens[p.pos+n]
+	}
+	return p.tokens[len(p.tokens)-1]
+}
+
+func (p *parser) peekIs(words ...string) bool {
+	for i, word := range words {
+		if !p.peekN(i).is(word) {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *parser) at(kind tokenKind) bool {
+	return p.peek().kind == kind
+}
+
+func (p *parser) next() token {
+	var tok = p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// accept consumes the given words when they are next in the input.
+func (p *parser) accept(words ...string) bool {
+	if !p.peekIs(words...) {
+		return false
+	}
+	p.pos += len(words)
+	return true
+}
+
+func (p *parser) expect(words ...string) error {
+	for _, word := range words {
+		if !p.accept(word) {
+			return p.errorf("expected %s, found %s", word, p.peek())
+		}
+	}
+	return nil
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	var tok = p.peek()
+	return &Error{File: p.file, Line: tok.line, Column: tok.column, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *parser) skipStatement() {
+	for !p.at(tokenEOF) && !p.accept(";") {
+		p.next()
+	}
+}
+
+// skipBalanced consumes tokens up to the first of stop found outside of
+// parentheses and returns the source text that was skipped.
+func (p *parser) skipBalanced(stop func(token) bool) string {
+	var (
+		depth int
+		start = p.peek().start
+		end   = start
+	)
+
+	for !p.at(tokenEOF) {
+		var tok = p.peek()
+		if depth == 0 && (stop(tok) || tok.is(")") || tok.is(",") || tok.is(";")) {
+			break
+		}
+		if tok.is("(") {
+			depth++
+		}
+		if tok.is(")") {
+			depth--
+		}
+		end = p.next().end
+	}
+
+	return strings.TrimSpace(p.src[start:end])
+}
+
+func (p *parser) parseIdent() (string, error) {
+	var tok = p.peek()
+	if tok.kind != tokenIdent && tok.kind != tokenQuotedIdent {
+		return "", p.errorf("expected identifier, found %s", tok)
+	}
+	p.next()
+
+	if tok.kind == tokenIdent {
+		return strings.ToLower(tok.text), nil
+	}
+	return tok.text, nil
+}
+
+// parseName reads a possibly schema qualified name and returns its last part.
+func (p *parser) parseName() (string, error) {
+	name, err := p.parseIdent()
+	if err != nil {
+		return "", err
+	}
+
+	for p.accept(".") {
+		name, err = p.parseIdent()
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return name, nil
+}
+
+func (p *parser) parseIdentList() ([]string, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for {
+		name, err := p.parseIdent()
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+
+		// ASC/DESC, NULLS FIRST and operator classes of index columns
+		p.skipBalanced(func(token) bool { return false })
+
+		if !p.accept(",") {
+			break
+		}
+	}
+
+	return names, p.expect(")")
+}
+
+func (p *parser) parseCreateTable() error {
+	p.expect("CREATE")
+	p.accept("UNLOGGED")
+	_ = p.accept("TEMP") || p.accept("TEMPORARY")
+	p.expect("TABLE")
+	var ifNotExists = p.accept("IF", "NOT", "EXISTS")
+
+	var nameToken = p.peek()
+	name, err := p.parseName()
+	if err != nil {
+		return err
+	}
+
+	var table = &schema.Table{Name: name, Comment: p.leadingComment(nameToken.line)}
+	if err := p.expect("("); err != nil {
+		return err
+	}
+
+	for !p.accept(")") {
+		if err := p.parseTableElement(table); err != nil {
+			return err
+		}
+
+		if !p.accept(",") && !p.peekIs(")") {
+			return p.errorf("expected \",\" or \")\", found %s", p.peek())
+		}
+	}
+
+	// INHERITS, PARTITION BY, WITH and TABLESPACE clauses
+	p.skipStatement()
+
+	if err := table.Resolve(); err != nil {
+		return fmt.Errorf("%s: %w", p.file, err)
+	}
+
+	if p.schema.Table(name) != nil {
+		if ifNotExists {
+			return nil
+		}
+		return &Error{File: p.file, Line: nameToken.line, Column: nameToken.column, Msg: fmt.Sprintf("table %q already exists", name)}
+	}
+
+	p.schema.Tables = append(p.schema.Tables, table)
+	return nil
+}
+
+func (p *parser) parseTableElement(table *schema.Table) error {
+	var constraintName string
+	if p.accept("CONSTRAINT") {
+		name, err := p.parseIdent()
+		if err != nil {
+			return err
+		}
+		constraintName = name
+	}
+
+	switch {
+	case p.accept("PRIMARY", "KEY"):
+		columns, err := p.parseIdentList()
+		if err != nil {
+			return err
+		}
+		if constraintName == "" {
+			constraintName = table.Name + "_pkey"
+		}
+		table.PrimaryKey, table.PrimaryKeyName = columns, constraintName
+		p.skipBalanced(func(token) bool { return false })
+
+	case p.accept("UNIQUE"):
+		p.accept("NULLS", "NOT", "DISTINCT")
+		columns, err := p.parseIdentList()
+		if err != nil {
+			return err
+		}
+		if constraintName == "" {
+			constraintName = table.Name + "_" + strings.Join(columns, "_") + "_key"
+		}
+		table.Indexes = append(table.Indexes, &schema.Index{Name: constraintName, Columns: columns, Unique: true})
+		p.skipBalanced(func(token) bool { return false })
+
+	case p.accept("FOREIGN", "KEY"):
+		columns, err := p.parseIdentList()
+		if err != nil {
+			return err
+		}
+		if err := p.expect("REFERENCES"); err != nil {
+			return err
+		}
+		if constraintName == "" {
+			constraintName = table.Name + "_" + strings.Join(columns, "_") + "_fkey"
+		}
+		foreignKey, err := p.parseReferences(constraintName, columns)
+		if err != nil {
+			return err
+		}
+		table.ForeignKeys = append(table.ForeignKeys, foreignKey)
+
+	case p.accept("CHECK"):
+		check, err := p.parseCheck(table, constraintName, "")
+		if err != nil {
+			return err
+		}
+		table.Checks = append(table.Checks, check)
+
+	case p.peekIs("EXCLUDE"), p.peekIs("LIKE"):
+		p.skipBalanced(func(token) bool { return false })
+
+	default:
+		if constraintName != "" {
+			return p.errorf("expected table constraint, found %s", p.peek())
+		}
+		return p.parseColumn(table)
+	}
+
+	return nil
+}
+
+func (p *parser) parseColumn(table *schema.Table) error {
+	var nameToken = p.peek()
+	name, err := p.parseIdent()
+	if err != nil {
+		return err
+	}
+
+	var column = &schema.Column{Name: name}
+	if err := p.parseType(column); err != nil {
+		return err
+	}
+
+	// serial types are integers NOT NULL DEFAULT nextval(...)
+	switch column.Type {
+	case "smallserial", "serial", "bigserial":
+		column.NotNull, column.HasDefault = true, true
+	}
+
+	for !p.peekIs(",") && !p.peekIs(")") && !p.peekIs(";") && !p.at(tokenEOF) {
+		var constraintName string
+		if p.accept("CONSTRAINT") {
+			if constraintName, err = p.parseIdent(); err != nil {
+				return err
+			}
+		}
+
+		switch {
+		case p.accept("NOT", "NULL"):
+			column.NotNull = true
+		case p.accept("NULL"):
+			column.NotNull = false
+		case p.accept("DEFAULT"):
+			if p.accept("NULL") {
+				// DEFAULT NULL, possibly cast, is the same as no default
+				p.skipBalanced(isColumnConstraintStart)
+				column.HasDefault, column.Default = false, ""
+				continue
+			}
+			column.HasDefault = true
+			column.Default = p.skipBalanced(isColumnConstraintStart)
+			if column.Default == "" {
+				return p.errorf("expected default expression, found %s", p.peek())
+			}
+		case p.accept("PRIMARY", "KEY"):
+			column.PrimaryKey = true
+			if constraintName == "" {
+				constraintName = table.Name + "_pkey"
+			}
+			table.PrimaryKey, table.PrimaryKeyName = []string{column.Name}, constraintName
+		case p.accept("UNIQUE"):
+			column.Unique = true
+			if constraintName == "" {
+				constraintName = table.Name + "_" + column.Name + "_key"
+			}
+			table.Indexes = append(table.Indexes, &schema.Index{Name: constraintName, Columns: []string{column.Name}, Unique: true})
+		case p.accept("REFERENCES"):
+			if constraintName == "" {
+				constraintName = table.Name + "_" + column.Name + "_fkey"
+			}
+			foreignKey, err := p.parseReferences(constraintName, []string{column.Name})
+			if err != nil {
+				return err
+			}
+			table.ForeignKeys = append(table.ForeignKeys, foreignKey)
+		case p.accept("CHECK"):
+			check, err := p.parseCheck(table, constraintName, column.Name)
+			if err != nil {
+				return err
+			}
+			table.Checks = append(table.Checks, check)
+		case p.accept("GENERATED"):
+			switch {
+			case p.accept("ALWAYS", "AS", "IDENTITY"), p.accept("BY", "DEFAULT", "AS", "IDENTITY"):
+				column.Identity = true
+				column.HasDefault = true
+				if p.peekIs("(") {
+					p.next()
+					p.skipBalanced(func(token) bool { return false })
+					if err := p.expect(")"); err != nil {
+						return err
+					}
+				}
+			case p.accept("ALWAYS", "AS"):
+				if err := p.expect("("); err != nil {
+					return err
+				}
+				column.Generated = true
+				column.HasDefault = true
+				column.Default = p.skipBalanced(func(token) bool { return false })
+				if err := p.expect(")"); err != nil {
+					return err
+				}
+				p.accept("STORED")
+			default:
+				return p.errorf("expected AS IDENTITY or AS (expression), found %s", p.peek())
+			}
+		case p.accept("COLLATE"):
+			if _, err := p.parseName(); err != nil {
+				return err
+			}
+		case p.accept("DEFERRABLE"), p.accept("NOT", "DEFERRABLE"),
+			p.accept("INITIALLY", "DEFERRED"), p.accept("INITIALLY", "IMMEDIATE"):
+		default:
+			return p.errorf("unexpected %s in definition of column %q", p.peek(), column.Name)
+		}
+	}
+
+	column.Comment = p.trailingComment()
+	if column.Comment == "" {
+		column.Comment = p.leadingComment(nameToken.line)
+	}
+
+	table.Columns = append(table.Columns, column)
+	return nil
+}
+
+func isColumnConstraintStart(tok token) bool {
+	for _, word := range []string{"NOT", "NULL", "PRIMARY", "UNIQUE", "REFERENCES", "CHECK", "CONSTRAINT", "GENERATED", "COLLATE", "DEFERRABLE", "INITIALLY"} {
+		if tok.is(word) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *parser) parseType(column *schema.Column) error {
+	name, err := p.parseName()
+	if err != nil {
+		return err
+	}
+
+	switch name {
+	case "double":
+		if err := p.expect("PRECISION"); err != nil {
+			return err
+		}
+		name = "double precision"
+	case "character", "char", "bit", "national":
+		if name == "national" {
+			if err := p.expect("CHARACTER"); err != nil {
+				return err
+			}
+			name = "character"
+		}
+		if p.accept("VARYING") {
+			name += " varying"
+		}
+	}
+
+	if p.accept("(") {
+		first, err := p.parseInt()
+		if err != nil {
+			return err
+		}
+		switch name {
+		case "numeric", "decimal":
+			column.Precision = first
+			if p.accept(",") {
+				if column.Scale, err = p.parseInt(); err != nil {
+					return err
+				}
+			}
+		case "timestamp", "time", "interval":
+			column.Precision = first
+		default:
+			column.Length = first
+		}
+		if err := p.expect(")"); err != nil {
+			return err
+		}
+	}
+
+	switch name {
+	case "timestamp", "time":
+		if p.accept("WITH", "TIME", "ZONE") {
+			name += " with time zone"
+		} else if p.accept("WITHOUT", "TIME", "ZONE") {
+			name += " without time zone"
+		}
+	case "interval":
+		for _, field := range []string{"YEAR", "MONTH", "DAY", "HOUR", "MINUTE", "SECOND", "TO"} {
+			for p.accept(field) {
+			}
+		}
+	}
+
+	for p.accept("[") {
+		column.Array = true
+		if p.at(tokenNumber) {
+			p.next()
+		}
+		if err := p.expect("]"); err != nil {
+			return err
+		}
+	}
+	if p.accept("ARRAY") {
+		column.Array = true
+		if p.accept("[") {
+			if p.at(tokenNumber) {
+				p.next()
+			}
+			if err := p.expect("]"); err != nil {
+				return err
+			}
+		}
+	}
+
+	column.Type = schema.NormalizeType(name)
+
+	return nil
+}
+
+func (p *parser) parseInt() (int, error) {
+	var tok = p.peek()
+	if tok.kind != tokenNumber {
+		return 0, p.errorf("expected number, found %s", tok)
+	}
+
+	value, err := strconv.Atoi(tok.text)
+	if err != nil {
+		return 0, p.errorf("expected integer, found %s", tok)
+	}
+	p.next()
+
+	return value, nil
+}
+
+func (p *parser) parseReferences(name string, columns []string) (*schema.ForeignKey, error) {
+	refTable, err := p.parseName()
+	if err != nil {
+		return nil, err
+	}
+
+	var foreignKey = &schema.ForeignKey{
+		Name:     name,
+		Columns:  columns,
+		RefTable: refTable,
+	}
+
+	if p.peekIs("(") {
+		if foreignKey.RefColumns, err = p.parseIdentList(); err != nil {
+			return nil, err
+		}
+	}
+
+	for {
+		switch {
+		case p.accept("MATCH"):
+			p.next()
+		case p.accept("ON", "DELETE"):
+			foreignKey.OnDelete = p.parseReferentialAction()
+		case p.accept("ON", "UPDATE"):
+			foreignKey.OnUpdate = p.parseReferentialAction()
+		default:
+			return foreignKey, nil
+		}
+	}
+}
+
+func (p *parser) parseReferentialAction() string {
+	for _, action := range [][]string{{"CASCADE"}, {"RESTRICT"}, {"NO", "ACTION"}, {"SET", "NULL"}, {"SET", "DEFAULT"}} {
+		if p.accept(action...) {
+			return strings.Join(action, " ")
+		}
+	}
+	return ""
+}
+
+// parseCheck reads the condition of a CHECK constraint. An unnamed check
+// gets the name Postgres gives it, <table>_<column>_check when the condition
+// uses a single column and <table>_check otherwise, numbered when the table
+// already has a check of that name. column is the column being defined, it
+// is not part of the table yet.
+func (p *parser) parseCheck(table *schema.Table, name, column string) (*schema.Check, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+
+	var (
+		start   = p.pos
+		check   = &schema.Check{Expression: p.skipBalanced(func(token) bool { return false })}
+		columns []string
+	)
+
+	for _, tok := range p.tokens[start:p.pos] {
+		var ident = tok.text
+		switch tok.kind {
+		case tokenIdent:
+			ident = strings.ToLower(ident)
+		case tokenQuotedIdent:
+		default:
+			continue
+		}
+		if (ident == column || table.HasColumn(ident)) && !contains(columns, ident) {
+			columns = append(columns, ident)
+		}
+	}
+
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+	p.accept("NO", "INHERIT")
+
+	check.Name = name
+	if check.Name == "" {
+		var prefix = table.Name + "_check"
+		if len(columns) == 1 {
+			prefix = table.Name + "_" + columns[0] + "_check"
+		}
+
+		check.Name = prefix
+		for i := 1; hasCheck(table, check.Name); i++ {
+			check.Name = prefix + strconv.Itoa(i)
+		}
+	}
+
+	return check, nil
+}
+
+func hasCheck(table *schema.Table, name string) bool {
+	for _, check := range table.Checks {
+		if check.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *parser) parseCreateIndex() error {
+	p.expect("CREATE")
+	var unique = p.accept("UNIQUE")
+	p.expect("INDEX")
+	p.accept("CONCURRENTLY")
+	p.accept("IF", "NOT", "EXISTS")
+
+	var name string
+	if !p.peekIs("ON") {
+		var err error
+		if name, err = p.parseName(); err != nil {
+			return err
+		}
+	}
+
+	if err := p.expect("ON"); err != nil {
+		return err
+	}
+	p.accept("ONLY")
+
+	tableName, err := p.parseName()
+	if err != nil {
+		return err
+	}
+
+	if p.accept("USING") {
+		p.next()
+	}
+
+	var (
+		columns    []string
+		expression bool
+	)
+	if err := p.expect("("); err != nil {
+		return err
+	}
+	for {
+		// expression indexes have no column to point at, keep their text
+		if (p.peek().kind == tokenIdent || p.peek().kind == tokenQuotedIdent) && !p.peekN(1).is("(") {
+			column, err := p.parseIdent()
+			if err != nil {
+				return err
+			}
+			columns = append(columns, column)
+		} else {
+			expression = true
+		}
+		p.skipBalanced(func(token) bool { return false })
+		if !p.accept(",") {
+			break
+		}
+	}
+	if err := p.expect(")"); err != nil {
+		return err
+	}
+
+	// INCLUDE, WITH and TABLESPACE clauses, a partial index keeps its WHERE
+	var predicate string
+	for !p.at(tokenEOF) && !p.accept(";") {
+		if p.accept("WHERE") {
+			predicate = p.skipBalanced(func(token) bool { return false })
+			continue
+		}
+		p.next()
+	}
+
+	var table = p.schema.Table(tableName)
+	if table == nil {
+		return nil
+	}
+
+	if name == "" {
+		name = tableName + "_" + strings.Join(columns, "_") + "_idx"
+	}
+	table.Indexes = append(table.Indexes, &schema.Index{Name: name, Columns: columns, Unique: unique, Expression: expression, Predicate: predicate})
+
+	return table.Resolve()
+}
@@ -0,0 +1,236 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+
+	"githubc.com/asadbekGo/generate-code/schema"
+)
+
+func parseTable(t *testing.T, src string) *schema.Table {
+	t.Helper()
+
+	s, err := Parse("test.sql", src)
+	if err != nil {
+		t.Fatalf("Parse(%q): %v", src, err)
+	}
+	if len(s.Tables) == 0 {
+		t.Fatalf("Parse(%q): no table", src)
+	}
+
+	return s.Tables[len(s.Tables)-1]
+}
+
+func TestParseColumn(t *testing.T) {
+	var tests = []struct {
+		name   string
+		column string
+		want   schema.Column
+	}{
+		{
+			name:   "default null",
+			column: "note VARCHAR(10) DEFAULT NULL",
+			want:   schema.Column{Name: "note", Type: "varchar", Length: 10},
+		},
+		{
+			name:   "default null cast",
+			column: "note TEXT DEFAULT NULL::text NOT NULL",
+			want:   schema.Column{Name: "note", Type: "text", NotNull: true},
+		},
+		{
+			name:   "default expression",
+			column: "created_at TIMESTAMP DEFAULT now() NOT NULL",
+			want:   schema.Column{Name: "created_at", Type: "timestamp", NotNull: true, HasDefault: true, Default: "now()"},
+		},
+		{
+			name:   "default string",
+			column: "status TEXT NOT NULL DEFAULT 'new'",
+			want:   schema.Column{Name: "status", Type: "text", NotNull: true, HasDefault: true, Default: "'new'"},
+		},
+		{
+			name:   "null",
+			column: "price NUMERIC(10, 2) NULL",
+			want:   schema.Column{Name: "price", Type: "numeric", Precision: 10, Scale: 2},
+		},
+		{
+			name:   "primary key",
+			column: "id UUID PRIMARY KEY",
+			want:   schema.Column{Name: "id", Type: "uuid", NotNull: true, PrimaryKey: true},
+		},
+		{
+			name:   "serial",
+			column: "id SERIAL PRIMARY KEY",
+			want:   schema.Column{Name: "id", Type: "serial", NotNull: true, HasDefault: true, PrimaryKey: true},
+		},
+		{
+			name:   "identity",
+			column: "id BIGINT GENERATED BY DEFAULT AS IDENTITY (START WITH 10) NOT NULL",
+			want:   schema.Column{Name: "id", Type: "bigint", NotNull: true, HasDefault: true, Identity: true},
+		},
+		{
+			name:   "generated",
+			column: "total NUMERIC GENERATED ALWAYS AS (price * 2) STORED",
+			want:   schema.Column{Name: "total", Type: "numeric", HasDefault: true, Generated: true, Default: "price * 2"},
+		},
+		{
+			name:   "named unique",
+			column: "code TEXT CONSTRAINT test_code_key UNIQUE",
+			want:   schema.Column{Name: "code", Type: "text", Unique: true},
+		},
+		{
+			name:   "check",
+			column: "age INT CHECK (age > 0) NOT NULL",
+			want:   schema.Column{Name: "age", Type: "integer", NotNull: true},
+		},
+		{
+			name:   "collate",
+			column: `name TEXT COLLATE "C" NOT NULL`,
+			want:   schema.Column{Name: "name", Type: "text", NotNull: true},
+		},
+		{
+			name:   "array",
+			column: "tags TEXT[] NOT NULL",
+			want:   schema.Column{Name: "tags", Type: "text", Array: true, NotNull: true},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var table = parseTable(t, "CREATE TABLE test ("+test.column+");")
+			if len(table.Columns) != 1 {
+				t.Fatalf("got %d columns, want 1", len(table.Columns))
+			}
+			if got := *table.Columns[0]; !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestParseTableConstraints(t *testing.T) {
+	var table = parseTable(t, `
+CREATE TABLE product_tag (
+	product_id UUID NOT NULL REFERENCES product(id) ON DELETE CASCADE,
+	tag_id UUID NOT NULL,
+	position INT NOT NULL DEFAULT 0,
+	CONSTRAINT product_tag_pkey PRIMARY KEY (product_id, tag_id),
+	FOREIGN KEY (tag_id) REFERENCES tag (id) ON UPDATE RESTRICT,
+	UNIQUE (tag_id, position),
+	CHECK (position >= 0)
+);
+CREATE INDEX product_tag_lower_idx ON product_tag (lower(position::text));
+CREATE UNIQUE INDEX product_tag_position_idx ON product_tag (position) WITH (fillfactor = 70) WHERE position > 0 AND tag_id IS NOT NULL;
+`)
+
+	if want := []string{"product_id", "tag_id"}; !reflect.DeepEqual(table.PrimaryKey, want) {
+		t.Errorf("primary key = %v, want %v", table.PrimaryKey, want)
+	}
+	for _, name := range table.PrimaryKey {
+		if column := table.Column(name); !column.PrimaryKey || !column.NotNull {
+			t.Errorf("column %s: PrimaryKey = %v, NotNull = %v", name, column.PrimaryKey, column.NotNull)
+		}
+	}
+
+	var foreignKeys = []schema.ForeignKey{
+		{Name: "product_tag_product_id_fkey", Columns: []string{"product_id"}, RefTable: "product", RefColumns: []string{"id"}, OnDelete: "CASCADE"},
+		{Name: "product_tag_tag_id_fkey", Columns: []string{"tag_id"}, RefTable: "tag", RefColumns: []string{"id"}, OnUpdate: "RESTRICT"},
+	}
+	if len(table.ForeignKeys) != len(foreignKeys) {
+		t.Fatalf("got %d foreign keys, want %d", len(table.ForeignKeys), len(foreignKeys))
+	}
+	for i, want := range foreignKeys {
+		if got := *table.ForeignKeys[i]; !reflect.DeepEqual(got, want) {
+			t.Errorf("foreign key %d = %+v, want %+v", i, got, want)
+		}
+		if column := table.Column(want.Columns[0]); column.References != table.ForeignKeys[i] {
+			t.Errorf("column %s does not reference %s", column.Name, want.Name)
+		}
+	}
+
+	var indexes = []schema.Index{
+		{Name: "product_tag_tag_id_position_key", Columns: []string{"tag_id", "position"}, Unique: true},
+		{Name: "product_tag_lower_idx", Expression: true},
+		{Name: "product_tag_position_idx", Columns: []string{"position"}, Unique: true, Predicate: "position > 0 AND tag_id IS NOT NULL"},
+	}
+	if len(table.Indexes) != len(indexes) {
+		t.Fatalf("got %d indexes, want %d", len(table.Indexes), len(indexes))
+	}
+	for i, want := range indexes {
+		if got := *table.Indexes[i]; !reflect.DeepEqual(got, want) {
+			t.Errorf("index %d = %+v, want %+v", i, got, want)
+		}
+	}
+
+	if table.Column("position").Unique {
+		t.Error("a partial unique index made position unique")
+	}
+
+	if table.PrimaryKeyName != "product_tag_pkey" {
+		t.Errorf("primary key name = %q", table.PrimaryKeyName)
+	}
+	if len(table.Checks) != 1 || *table.Checks[0] != (schema.Check{Name: "product_tag_position_check", Expression: "position >= 0"}) {
+		t.Errorf("checks = %+v", table.Checks)
+	}
+}
+
+func TestParseComments(t *testing.T) {
+	var table = parseTable(t, `
+-- Product is something to sell.
+CREATE TABLE product (
+	-- Name shown to buyers.
+	name TEXT NOT NULL,
+	price NUMERIC NOT NULL -- in cents
+);
+COMMENT ON COLUMN product.price IS 'Price in cents';
+`)
+
+	if table.Comment != "Product is something to sell." {
+		t.Errorf("table comment = %q", table.Comment)
+	}
+
+	var comments = map[string]string{
+		"name":  "Name shown to buyers.",
+		"price": "Price in cents",
+	}
+	for name, want := range comments {
+		if got := table.Column(name).Comment; got != want {
+			t.Errorf("column %s comment = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	var tests = []struct {
+		name string
+		src  string
+		want string
+	}{
+		{
+			name: "missing default",
+			src:  "CREATE TABLE t (\n\tname TEXT DEFAULT\n);",
+			want: `test.sql:3:1: expected default expression, found ")"`,
+		},
+		{
+			name: "unknown column constraint",
+			src:  "CREATE TABLE t (name TEXT BOGUS);",
+			want: `test.sql:1:27: unexpected "BOGUS" in definition of column "name"`,
+		},
+		{
+			name: "unknown primary key column",
+			src:  "CREATE TABLE t (name TEXT, PRIMARY KEY (id));",
+			want: `test.sql: table t: primary key column "id" does not exist`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := Parse("test.sql", test.src)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if err.Error() != test.want {
+				t.Errorf("got %q, want %q", err.Error(), test.want)
+			}
+		})
+	}
+}
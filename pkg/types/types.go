@@ -0,0 +1,107 @@
+package types
+
+import (
+	"strings"
+
+	"githubc.com/asadbekGo/generate-code/schema"
+)
+
+// Struct is the proto type json and jsonb columns are mapped to.
+const Struct = "google.protobuf.Struct"
+
+// maxDoublePrecision is the largest numeric precision a double holds
+// without losing digits, wider numeric columns are mapped to string.
+const maxDoublePrecision = 15
+
+// protoTypes maps the normalized SQL types to their default proto type,
+// unknown types are mapped to string.
+var protoTypes = map[string]string{
+	"smallint":         "int32",
+	"smallserial":      "int32",
+	"integer":          "int32",
+	"serial":           "int32",
+	"bigint":           "int64",
+	"bigserial":        "int64",
+	"real":             "float",
+	"double precision": "double",
+	"numeric":          "double",
+	"boolean":          "bool",
+	"bytea":            "bytes",
+	"json":             Struct,
+	"jsonb":            Struct,
+}
+
+// goTypes maps proto field types to the Go type protoc-gen-go generates.
+var goTypes = map[string]string{
+	"double":   "float64",
+	"float":    "float32",
+	"int32":    "int32",
+	"sint32":   "int32",
+	"sfixed32": "int32",
+	"int64":    "int64",
+	"sint64":   "int64",
+	"sfixed64": "int64",
+	"uint32":   "uint32",
+	"fixed32":  "uint32",
+	"uint64":   "uint64",
+	"fixed64":  "uint64",
+	"bool":     "bool",
+	"string":   "string",
+	"bytes":    "[]byte",
+	Struct:     "*structpb.Struct",
+}
+
+// textTypes are the SQL types scanned into a string as they are, other
+// types mapped to string are selected as ::text.
+var textTypes = map[string]bool{
+	"char":        true,
+	"varchar":     true,
+	"text":        true,
+	"citext":      true,
+	"name":        true,
+	"uuid":        true,
+	"date":        true,
+	"time":        true,
+	"timetz":      true,
+	"timestamp":   true,
+	"timestamptz": true,
+}
+
+// Proto returns the default proto type of a column, arrays are repeated.
+func Proto(column *schema.Column) string {
+	protoType, ok := protoTypes[column.Type]
+	if !ok || column.Type == "numeric" && column.Precision > maxDoublePrecision {
+		protoType = "string"
+	}
+
+	if column.Array {
+		return Repeated(protoType)
+	}
+	return protoType
+}
+
+// Repeated returns the repeated form of a scalar proto type. Repeated
+// messages are not supported, their elements are kept as json strings.
+func Repeated(protoType string) string {
+	if protoType == Struct {
+		protoType = "string"
+	}
+	return "repeated " + protoType
+}
+
+// Go returns the Go type generated for a proto field type and whether the
+// proto type is supported.
+func Go(protoType string) (string, bool) {
+	if elem := strings.TrimPrefix(protoType, "repeated "); elem != protoType {
+		goType, ok := goTypes[elem]
+		return "[]" + goType, ok && elem != Struct
+	}
+
+	goType, ok := goTypes[protoType]
+	return goType, ok
+}
+
+// IsText reports whether the SQL type is scanned into a string as it is.
+func IsText(sqlType string) bool {
+	return textTypes[sqlType]
+}
@@ -0,0 +1,53 @@
+package types
+
+import (
+	"testing"
+
+	"githubc.com/asadbekGo/generate-code/schema"
+)
+
+func TestProto(t *testing.T) {
+	var tests = []struct {
+		column schema.Column
+		want   string
+	}{
+		{schema.Column{Type: "integer"}, "int32"},
+		{schema.Column{Type: "bigserial"}, "int64"},
+		{schema.Column{Type: "numeric", Precision: 10, Scale: 2}, "double"},
+		{schema.Column{Type: "numeric", Precision: 20, Scale: 2}, "string"},
+		{schema.Column{Type: "jsonb"}, Struct},
+		{schema.Column{Type: "uuid"}, "string"},
+		{schema.Column{Type: "inet"}, "string"},
+		{schema.Column{Type: "integer", Array: true}, "repeated int32"},
+		{schema.Column{Type: "jsonb", Array: true}, "repeated string"},
+	}
+
+	for _, test := range tests {
+		if got := Proto(&test.column); got != test.want {
+			t.Errorf("Proto(%+v) = %q, want %q", test.column, got, test.want)
+		}
+	}
+}
+
+func TestGo(t *testing.T) {
+	var tests = []struct {
+		protoType string
+		want      string
+		ok        bool
+	}{
+		{"double", "float64", true},
+		{"sfixed64", "int64", true},
+		{"bytes", "[]byte", true},
+		{Struct, "*structpb.Struct", true},
+		{"repeated int32", "[]int32", true},
+		{"repeated " + Struct, "[]*structpb.Struct", false},
+		{"Money", "", false},
+	}
+
+	for _, test := range tests {
+		got, ok := Go(test.protoType)
+		if got != test.want || ok != test.ok {
+			t.Errorf("Go(%q) = %q, %v, want %q, %v", test.protoType, got, ok, test.want, test.ok)
+		}
+	}
+}
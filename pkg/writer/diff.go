@@ -0,0 +1,183 @@
+package writer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines shown around a change.
+const diffContext = 3
+
+type edit struct {
+	op   byte // ' ', '-' or '+'
+	line string
+}
+
+// Diff returns the unified diff turning from into to, or "" when both are
+// equal. fromName and toName are printed in the ---/+++ header.
+func Diff(fromName, toName, from, to string) string {
+	if from == to {
+		return ""
+	}
+
+	var (
+		edits  = diffLines(splitLines(from), splitLines(to))
+		hunks  [][2]int
+		before = make([][2]int, len(edits)+1)
+		out    strings.Builder
+	)
+
+	for i, e := range edits {
+		before[i+1] = before[i]
+		if e.op != '+' {
+			before[i+1][0]++
+		}
+		if e.op != '-' {
+			before[i+1][1]++
+		}
+
+		if e.op == ' ' {
+			continue
+		}
+
+		var start, end = i - diffContext, i + diffContext + 1
+		if start < 0 {
+			start = 0
+		}
+		if end > len(edits) {
+			end = len(edits)
+		}
+
+		if len(hunks) > 0 && start <= hunks[len(hunks)-1][1] {
+			hunks[len(hunks)-1][1] = end
+		} else {
+			hunks = append(hunks, [2]int{start, end})
+		}
+	}
+
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", fromName, toName)
+
+	for _, hunk := range hunks {
+		var (
+			fromCount = before[hunk[1]][0] - before[hunk[0]][0]
+			toCount   = before[hunk[1]][1] - before[hunk[0]][1]
+		)
+
+		fmt.Fprintf(&out, "@@ -%s +%s @@\n",
+			hunkRange(before[hunk[0]][0], fromCount), hunkRange(before[hunk[0]][1], toCount))
+
+		for _, e := range edits[hunk[0]:hunk[1]] {
+			out.WriteByte(e.op)
+			out.WriteString(e.line)
+			if !strings.HasSuffix(e.line, "\n") {
+				out.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+	}
+
+	return out.String()
+}
+
+// hunkRange formats the start,count pair of a hunk header, an empty range
+// points at the line before it.
+func hunkRange(offset, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", offset)
+	}
+	return fmt.Sprintf("%d,%d", offset+1, count)
+}
+
+// splitLines splits text after every newline, the last line keeps no
+// newline when the text does not end with one.
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	var lines = strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// diffLines returns the shortest edit script from a to b using the Myers
+// algorithm. The trace keeps only the 2d+1 diagonals step d can reach, so
+// memory grows with the square of the number of edits, not of the file.
+func diffLines(a, b []string) []edit {
+	var (
+		n, m   = len(a), len(b)
+		max    = n + m
+		offset = max + 1
+		v      = make([]int, 2*max+3)
+		trace  [][]int
+	)
+
+search:
+	for d := 0; d <= max; d++ {
+		trace = append(trace, append([]int(nil), v[offset-d:offset+d+1]...))
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+
+			var y = x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+
+			if x >= n && y >= m {
+				break search
+			}
+		}
+	}
+
+	var (
+		edits []edit
+		x, y  = n, m
+	)
+
+	for d := len(trace) - 1; d >= 0; d-- {
+		var (
+			v            = trace[d] // diagonal k is at v[d+k]
+			k            = x - y
+			prevX, prevY int
+		)
+
+		if d > 0 {
+			var prevK = k - 1
+			if k == -d || (k != d && v[d+k-1] < v[d+k+1]) {
+				prevK = k + 1
+			}
+			prevX = v[d+prevK]
+			prevY = prevX - prevK
+		}
+
+		for x > prevX && y > prevY {
+			edits = append(edits, edit{' ', a[x-1]})
+			x--
+			y--
+		}
+
+		if d > 0 {
+			if x == prevX {
+				edits = append(edits, edit{'+', b[y-1]})
+			} else {
+				edits = append(edits, edit{'-', a[x-1]})
+			}
+		}
+
+		x, y = prevX, prevY
+	}
+
+	for i, j := 0, len(edits)-1; i < j; i, j = i+1, j-1 {
+		edits[i], edits[j] = edits[j], edits[i]
+	}
+
+	return edits
+}
@@ -0,0 +1,122 @@
+package writer
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	var tests = []struct {
+		name     string
+		from, to string
+		want     string
+	}{
+		{
+			name: "equal",
+			from: "a\nb\n",
+			to:   "a\nb\n",
+			want: "",
+		},
+		{
+			name: "new file",
+			from: "",
+			to:   "a\nb\n",
+			want: "--- a/x\n+++ b/x\n@@ -0,0 +1,2 @@\n+a\n+b\n",
+		},
+		{
+			name: "emptied file",
+			from: "a\nb\n",
+			to:   "",
+			want: "--- a/x\n+++ b/x\n@@ -1,2 +0,0 @@\n-a\n-b\n",
+		},
+		{
+			name: "changed line with context",
+			from: "1\n2\n3\n4\n5\n6\n7\n8\n9\n",
+			to:   "1\n2\n3\n4\nfive\n6\n7\n8\n9\n",
+			want: "--- a/x\n+++ b/x\n@@ -2,7 +2,7 @@\n 2\n 3\n 4\n-5\n+five\n 6\n 7\n 8\n",
+		},
+		{
+			name: "separate hunks",
+			from: "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n",
+			to:   "one\n2\n3\n4\n5\n6\n7\n8\n9\n10\neleven\n",
+			want: "--- a/x\n+++ b/x\n@@ -1,4 +1,4 @@\n-1\n+one\n 2\n 3\n 4\n@@ -8,3 +8,4 @@\n 8\n 9\n 10\n+eleven\n",
+		},
+		{
+			name: "close changes share a hunk",
+			from: "1\n2\n3\n4\n5\n",
+			to:   "1\ntwo\n3\nfour\n5\n",
+			want: "--- a/x\n+++ b/x\n@@ -1,5 +1,5 @@\n 1\n-2\n+two\n 3\n-4\n+four\n 5\n",
+		},
+		{
+			name: "no newline at end of file",
+			from: "a\nb",
+			to:   "a\nb\n",
+			want: "--- a/x\n+++ b/x\n@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+b\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := Diff("a/x", "b/x", test.from, test.to); got != test.want {
+				t.Errorf("got\n%s\nwant\n%s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	var tests = []struct {
+		name  string
+		a, b  []string
+		edits int
+	}{
+		{"empty", nil, nil, 0},
+		{"insert", []string{"a", "c"}, []string{"a", "b", "c"}, 1},
+		{"delete", []string{"a", "b", "c"}, []string{"a", "c"}, 1},
+		{"replace all", []string{"a", "b"}, []string{"c", "d"}, 4},
+		{"move", []string{"a", "b", "c", "d"}, []string{"b", "c", "d", "a"}, 2},
+		{"large", numbered(2000, 0), numbered(2000, 7), 2000 / 7 * 2},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				edits   = diffLines(test.a, test.b)
+				a, b    []string
+				changed int
+			)
+
+			for _, e := range edits {
+				if e.op != '+' {
+					a = append(a, e.line)
+				}
+				if e.op != '-' {
+					b = append(b, e.line)
+				}
+				if e.op != ' ' {
+					changed++
+				}
+			}
+
+			if strings.Join(a, "") != strings.Join(test.a, "") || strings.Join(b, "") != strings.Join(test.b, "") {
+				t.Fatalf("edits do not turn a into b: %v", edits)
+			}
+			if changed != test.edits {
+				t.Errorf("got %d changed lines, want %d", changed, test.edits)
+			}
+		})
+	}
+}
+
+// numbered returns n lines, every every-th one is changed when every > 0.
+func numbered(n, every int) []string {
+	var lines = make([]string, n)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("line %d\n", i)
+		if every > 0 && i%every == every-1 {
+			lines[i] = fmt.Sprintf("changed %d\n", i)
+		}
+	}
+	return lines
+}
@@ -0,0 +1,122 @@
+package writer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Protected regions are blocks of hand-written code inside a generated
+// file, e.g.
+//
+//	// generate:begin custom filters
+//	if req.GetSearch() != "" { ... }
+//	// generate:end
+//
+// The text after "generate:begin" names the region. On regeneration the
+// body of every region is taken from the file on disk.
+const (
+	regionBegin = "// generate:begin"
+	regionEnd   = "// generate:end"
+)
+
+type region struct {
+	name string
+	body string
+}
+
+// parseRegions returns the protected regions of text in order.
+func parseRegions(filename, text string) ([]region, error) {
+	var (
+		regions []region
+		current *region
+		begin   int
+	)
+
+	for i, line := range splitLines(text) {
+		var trimmed = strings.TrimSpace(line)
+
+		switch {
+		case strings.HasPrefix(trimmed, regionBegin):
+			if current != nil {
+				return nil, fmt.Errorf("%s:%d: nested %q, region %q opened on line %d is not closed", filename, i+1, regionBegin, current.name, begin)
+			}
+			current = &region{name: strings.TrimSpace(strings.TrimPrefix(trimmed, regionBegin))}
+			begin = i + 1
+
+		case strings.HasPrefix(trimmed, regionEnd):
+			if current == nil {
+				return nil, fmt.Errorf("%s:%d: %q without %q", filename, i+1, regionEnd, regionBegin)
+			}
+			regions = append(regions, *current)
+			current = nil
+
+		case current != nil:
+			current.body += line
+		}
+	}
+
+	if current != nil {
+		return nil, fmt.Errorf("%s:%d: region %q is not closed with %q", filename, begin, current.name, regionEnd)
+	}
+
+	return regions, nil
+}
+
+// mergeRegions copies the protected regions of current into the freshly
+// rendered data. Regions are matched by name, regions sharing a name by
+// their order. A non-empty region data has no place for is an error, its
+// code would be lost otherwise.
+func mergeRegions(filename, current, data string) (string, error) {
+	existing, err := parseRegions(filename, current)
+	if err != nil {
+		return "", err
+	}
+
+	if len(existing) == 0 {
+		return data, nil
+	}
+
+	var bodies = map[string][]string{}
+	for _, r := range existing {
+		bodies[r.name] = append(bodies[r.name], r.body)
+	}
+
+	var (
+		out    strings.Builder
+		inside bool
+	)
+
+	for _, line := range splitLines(data) {
+		var trimmed = strings.TrimSpace(line)
+
+		switch {
+		case strings.HasPrefix(trimmed, regionBegin):
+			out.WriteString(line)
+
+			var name = strings.TrimSpace(strings.TrimPrefix(trimmed, regionBegin))
+			if kept := bodies[name]; len(kept) > 0 {
+				out.WriteString(kept[0])
+				bodies[name] = kept[1:]
+				inside = true
+			}
+
+		case strings.HasPrefix(trimmed, regionEnd):
+			inside = false
+			out.WriteString(line)
+
+		case !inside:
+			out.WriteString(line)
+		}
+	}
+
+	for _, r := range existing {
+		for _, body := range bodies[r.name] {
+			if strings.TrimSpace(body) != "" {
+				return "", fmt.Errorf("%s: custom region %q is not part of the generated file any more, move its code before regenerating", filename, r.name)
+			}
+		}
+		delete(bodies, r.name)
+	}
+
+	return out.String(), nil
+}
@@ -0,0 +1,98 @@
+package writer
+
+import "testing"
+
+func TestMergeRegions(t *testing.T) {
+	var tests = []struct {
+		name    string
+		current string
+		data    string
+		want    string
+		wantErr string
+	}{
+		{
+			name:    "no regions",
+			current: "a\nb\n",
+			data:    "a\nc\n",
+			want:    "a\nc\n",
+		},
+		{
+			name:    "body is kept",
+			current: "func a() {\n\t// generate:begin filters\n\tcustom()\n\t// generate:end\n}\n",
+			data:    "func b() {\n\t// generate:begin filters\n\t// generate:end\n}\n",
+			want:    "func b() {\n\t// generate:begin filters\n\tcustom()\n\t// generate:end\n}\n",
+		},
+		{
+			name:    "generated body is replaced",
+			current: "// generate:begin x\nmine\n// generate:end\n",
+			data:    "// generate:begin x\ndefault\n// generate:end\n",
+			want:    "// generate:begin x\nmine\n// generate:end\n",
+		},
+		{
+			name:    "matched by name",
+			current: "// generate:begin a\nA\n// generate:end\n// generate:begin b\nB\n// generate:end\n",
+			data:    "// generate:begin b\n// generate:end\nmiddle\n// generate:begin a\n// generate:end\n",
+			want:    "// generate:begin b\nB\n// generate:end\nmiddle\n// generate:begin a\nA\n// generate:end\n",
+		},
+		{
+			name:    "same name matched in order",
+			current: "// generate:begin x\n1\n// generate:end\n// generate:begin x\n2\n// generate:end\n",
+			data:    "// generate:begin x\n// generate:end\n// generate:begin x\n// generate:end\n",
+			want:    "// generate:begin x\n1\n// generate:end\n// generate:begin x\n2\n// generate:end\n",
+		},
+		{
+			name:    "new region stays empty",
+			current: "// generate:begin a\nA\n// generate:end\n",
+			data:    "// generate:begin a\n// generate:end\n// generate:begin b\n// generate:end\n",
+			want:    "// generate:begin a\nA\n// generate:end\n// generate:begin b\n// generate:end\n",
+		},
+		{
+			name:    "empty dropped region",
+			current: "// generate:begin gone\n\n// generate:end\n",
+			data:    "a\n",
+			want:    "a\n",
+		},
+		{
+			name:    "dropped region with code",
+			current: "// generate:begin gone\ncode()\n// generate:end\n",
+			data:    "a\n",
+			wantErr: `a.go: custom region "gone" is not part of the generated file any more, move its code before regenerating`,
+		},
+		{
+			name:    "nested region",
+			current: "// generate:begin a\n// generate:begin b\n// generate:end\n",
+			data:    "a\n",
+			wantErr: `a.go:2: nested "// generate:begin", region "a" opened on line 1 is not closed`,
+		},
+		{
+			name:    "end without begin",
+			current: "a\n// generate:end\n",
+			data:    "a\n",
+			wantErr: `a.go:2: "// generate:end" without "// generate:begin"`,
+		},
+		{
+			name:    "region not closed",
+			current: "a\n// generate:begin a\ncode()\n",
+			data:    "a\n",
+			wantErr: `a.go:2: region "a" is not closed with "// generate:end"`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := mergeRegions("a.go", test.current, test.data)
+			if test.wantErr != "" {
+				if err == nil || err.Error() != test.wantErr {
+					t.Fatalf("got error %v, want %q", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != test.want {
+				t.Errorf("got\n%s\nwant\n%s", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package writer
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+type Writer struct {
+	Root   string
+	Force  bool
+	DryRun bool
+	Check  bool
+
+	// Stale lists the files whose content differs from the rendered output.
+	Stale []string
+}
+
+func New(root string, force, dryRun, check bool) *Writer {
+	return &Writer{
+		Root:   root,
+		Force:  force,
+		DryRun: dryRun,
+		Check:  check,
+	}
+}
+
+// WriteFile writes data to filename relative to the output root keeping
+// the protected regions of the existing file. Files that are already up
+// to date are left alone, existing files are only replaced when Force is
+// set. DryRun prints a unified diff against the file on disk, headed by a
+// note when a run without Force skips the file, and Check only records the
+// file as stale.
+func (w *Writer) WriteFile(filename string, data string) error {
+
+	var path = filepath.Join(w.Root, filename)
+
+	current, err := os.ReadFile(path)
+	exists := err == nil
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	if exists {
+		data, err = mergeRegions(path, string(current), data)
+		if err != nil {
+			return err
+		}
+	}
+
+	if exists && string(current) == data {
+		return nil
+	}
+
+	w.Stale = append(w.Stale, path)
+
+	var skip = exists && !w.Force
+
+	if w.DryRun {
+		var name = filepath.ToSlash(filename)
+		var from = "a/" + name
+		if !exists {
+			from = os.DevNull
+		}
+		if skip {
+			fmt.Printf("# %s exists, it is skipped without -force\n", name)
+		}
+		fmt.Print(Diff(from, "b/"+name, string(current), data))
+		return nil
+	}
+
+	if w.Check {
+		return nil
+	}
+
+	if skip {
+		log.Println("Skip existing file (use -force to overwrite):", path)
+		return nil
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, []byte(data), 0644)
+}
@@ -0,0 +1,110 @@
+package writer
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriteFile(t *testing.T) {
+	const (
+		old  = "package a\n"
+		data = "package b\n"
+	)
+
+	var tests = []struct {
+		name                 string
+		exists               bool
+		force, dryRun, check bool
+		wantFile             string
+		wantDiff, wantStale  bool
+		wantSkip             bool
+	}{
+		{name: "new file", wantFile: data, wantStale: true},
+		{name: "existing file is skipped", exists: true, wantFile: old, wantStale: true},
+		{name: "existing file is forced", exists: true, force: true, wantFile: data, wantStale: true},
+		{name: "dry run of new file", dryRun: true, wantDiff: true, wantStale: true},
+		{name: "dry run of existing file", exists: true, dryRun: true, wantFile: old, wantDiff: true, wantStale: true, wantSkip: true},
+		{name: "forced dry run", exists: true, force: true, dryRun: true, wantFile: old, wantDiff: true, wantStale: true},
+		{name: "check", exists: true, check: true, wantFile: old, wantStale: true},
+		{name: "check with dry run", exists: true, check: true, dryRun: true, wantFile: old, wantDiff: true, wantStale: true, wantSkip: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				root = t.TempDir()
+				path = filepath.Join(root, "a", "a.go")
+				w    = New(root, test.force, test.dryRun, test.check)
+			)
+
+			if test.exists {
+				if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte(old), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var diff = captureStdout(t, func() {
+				if err := w.WriteFile("a/a.go", data); err != nil {
+					t.Fatal(err)
+				}
+			})
+
+			body, _ := os.ReadFile(path)
+			if string(body) != test.wantFile {
+				t.Errorf("file = %q, want %q", body, test.wantFile)
+			}
+			if (diff != "") != test.wantDiff {
+				t.Errorf("diff = %q, want a diff: %v", diff, test.wantDiff)
+			}
+			if skip := strings.HasPrefix(diff, "# a/a.go exists, it is skipped without -force\n---"); skip != test.wantSkip {
+				t.Errorf("diff = %q, want a skip note: %v", diff, test.wantSkip)
+			}
+			if (len(w.Stale) > 0) != test.wantStale {
+				t.Errorf("stale = %v, want stale: %v", w.Stale, test.wantStale)
+			}
+		})
+	}
+}
+
+func TestWriteFileUpToDate(t *testing.T) {
+	var root = t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "a.go"), []byte("package a\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var w = New(root, false, false, true)
+	if err := w.WriteFile("a.go", "package a\n"); err != nil {
+		t.Fatal(err)
+	}
+	if len(w.Stale) != 0 {
+		t.Errorf("stale = %v", w.Stale)
+	}
+}
+
+func captureStdout(t *testing.T, f func()) string {
+	t.Helper()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout = os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	f()
+	w.Close()
+
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(out)
+}
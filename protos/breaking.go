@@ -0,0 +1,228 @@
+package protos
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// protoFile is the part of a generated proto file that clients depend on.
+type protoFile struct {
+	messages map[string]*protoMessage
+	enums    map[string]*protoMessage
+	services map[string]map[string]protoRPC
+}
+
+// protoMessage is a message, or an enum whose values are fields without a
+// label and type.
+type protoMessage struct {
+	fields   map[string]protoField
+	reserved map[int]bool
+}
+
+type protoField struct {
+	label  string // "", "optional" or "repeated"
+	typ    string
+	number int
+}
+
+type protoRPC struct {
+	request  string
+	response string
+}
+
+var (
+	serviceStart = regexp.MustCompile(`^\s*service (\w+) \{\s*$`)
+	serviceRPC   = regexp.MustCompile(`^\s*rpc (\w+)\((\w+)\) returns \(([\w.]+)\)`)
+	fieldLine    = regexp.MustCompile(`^\s*(?:(optional|repeated) )?([\w.]+) (\w+) = (\d+);`)
+	reservedLine = regexp.MustCompile(`^\s*reserved ([\d, ]+);`)
+)
+
+// parseProto reads the services, messages and enums of a generated proto
+// file.
+func parseProto(text string) protoFile {
+	var (
+		file = protoFile{
+			messages: map[string]*protoMessage{},
+			enums:    map[string]*protoMessage{},
+			services: map[string]map[string]protoRPC{},
+		}
+		message *protoMessage
+		enum    bool
+		service map[string]protoRPC
+	)
+
+	for _, line := range strings.Split(text, "\n") {
+		switch {
+		case messageStart.MatchString(line):
+			message, enum = &protoMessage{fields: map[string]protoField{}, reserved: map[int]bool{}}, false
+			file.messages[messageStart.FindStringSubmatch(line)[2]] = message
+
+		case enumStart.MatchString(line):
+			message, enum = &protoMessage{fields: map[string]protoField{}, reserved: map[int]bool{}}, true
+			file.enums[enumStart.FindStringSubmatch(line)[2]] = message
+
+		case serviceStart.MatchString(line):
+			service = map[string]protoRPC{}
+			file.services[serviceStart.FindStringSubmatch(line)[1]] = service
+
+		case strings.TrimSpace(line) == "}":
+			message, service = nil, nil
+
+		case service != nil && serviceRPC.MatchString(line):
+			var match = serviceRPC.FindStringSubmatch(line)
+			service[match[1]] = protoRPC{request: match[2], response: match[3]}
+
+		case message != nil && reservedLine.MatchString(line):
+			for _, number := range strings.Split(reservedLine.FindStringSubmatch(line)[1], ",") {
+				if n, err := strconv.Atoi(strings.TrimSpace(number)); err == nil {
+					message.reserved[n] = true
+				}
+			}
+
+		case message != nil && enum && enumValue.MatchString(line):
+			var (
+				match     = enumValue.FindStringSubmatch(line)
+				number, _ = strconv.Atoi(match[3])
+			)
+			message.fields[match[2]] = protoField{number: number}
+
+		case message != nil && !enum && fieldLine.MatchString(line):
+			var (
+				match     = fieldLine.FindStringSubmatch(line)
+				number, _ = strconv.Atoi(match[4])
+			)
+			message.fields[match[3]] = protoField{label: match[1], typ: match[2], number: number}
+		}
+	}
+
+	return file
+}
+
+// breakingChanges describes the changes from the old to the new version of
+// a proto file that break clients built against the old one: field and
+// enum value numbers reused or moved, field types and labels changed, RPCs
+// removed or changed and messages and enums removed or renamed.
+func breakingChanges(filename, old, new string) []string {
+	var (
+		before  = parseProto(old)
+		after   = parseProto(new)
+		changes []string
+	)
+
+	for _, name := range sortedKeys(before.services) {
+		for _, rpc := range sortedKeys(before.services[name]) {
+			var (
+				was       = before.services[name][rpc]
+				now, kept = after.services[name][rpc]
+			)
+			switch {
+			case !kept:
+				changes = append(changes, fmt.Sprintf("%s: rpc %s.%s removed", filename, name, rpc))
+			case was != now:
+				changes = append(changes, fmt.Sprintf("%s: rpc %s.%s changed from (%s) returns (%s) to (%s) returns (%s)", filename, name, rpc, was.request, was.response, now.request, now.response))
+			}
+		}
+	}
+
+	changes = append(changes, blockChanges(filename, "message", "field", before.messages, after.messages)...)
+	changes = append(changes, blockChanges(filename, "enum", "value", before.enums, after.enums)...)
+
+	return changes
+}
+
+// blockChanges compares the messages or enums of two versions of a file,
+// item names their fields or values.
+func blockChanges(filename, kind, item string, before, after map[string]*protoMessage) []string {
+	var changes []string
+
+	for _, name := range sortedKeys(before) {
+		var was = before[name]
+
+		now, kept := after[name]
+		if !kept {
+			if renamed := renamedMessage(was, before, after); renamed != "" {
+				changes = append(changes, fmt.Sprintf("%s: %s %s renamed to %s", filename, kind, name, renamed))
+			} else {
+				changes = append(changes, fmt.Sprintf("%s: %s %s removed", filename, kind, name))
+			}
+			continue
+		}
+
+		var numbers = map[int]string{}
+		for field, f := range was.fields {
+			numbers[f.number] = field
+		}
+
+		for _, field := range sortedKeys(now.fields) {
+			var f = now.fields[field]
+
+			if previous, ok := numbers[f.number]; ok && previous != field {
+				changes = append(changes, fmt.Sprintf("%s: %s %s: %s number %d of %s reused by %s", filename, kind, name, item, f.number, previous, field))
+			}
+			if was.reserved[f.number] {
+				changes = append(changes, fmt.Sprintf("%s: %s %s: %s %s reuses reserved number %d", filename, kind, name, item, field, f.number))
+			}
+
+			old, ok := was.fields[field]
+			if !ok {
+				continue
+			}
+			if old.number != f.number {
+				changes = append(changes, fmt.Sprintf("%s: %s %s: %s %s renumbered from %d to %d", filename, kind, name, item, field, old.number, f.number))
+			}
+			if old.typ != f.typ {
+				changes = append(changes, fmt.Sprintf("%s: %s %s: field %s changed type from %s to %s", filename, kind, name, field, old.typ, f.typ))
+			}
+			if old.label != f.label {
+				changes = append(changes, fmt.Sprintf("%s: %s %s: field %s changed from %s to %s", filename, kind, name, field, labelName(old.label), labelName(f.label)))
+			}
+		}
+	}
+
+	return changes
+}
+
+// renamedMessage returns the message or enum new to after that has the
+// fields of the removed one, "" when there is none.
+func renamedMessage(removed *protoMessage, before, after map[string]*protoMessage) string {
+	for _, name := range sortedKeys(after) {
+		if _, ok := before[name]; ok {
+			continue
+		}
+		if sameFields(removed.fields, after[name].fields) {
+			return name
+		}
+	}
+	return ""
+}
+
+func sameFields(a, b map[string]protoField) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for name, field := range a {
+		if b[name] != field {
+			return false
+		}
+	}
+	return true
+}
+
+func labelName(label string) string {
+	if label == "" {
+		return "required"
+	}
+	return label
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	var keys []string
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,132 @@
+package protos
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const breakingBase = `syntax="proto3";
+
+service TaskService {
+    rpc GetByIDTask(TaskPrimaryKey) returns (Task) {}
+    rpc DeleteTask(TaskPrimaryKey) returns (google.protobuf.Empty) {}
+}
+
+message TaskPrimaryKey {
+    string id = 1;
+}
+
+message Task {
+    reserved 3;
+    string id = 1;
+    optional string title = 2;
+    Status status = 4;
+}
+
+enum Status {
+    STATUS_UNSPECIFIED = 0;
+    STATUS_NEW = 1;
+    STATUS_DONE = 2;
+}
+`
+
+func TestBreakingChanges(t *testing.T) {
+	var tests = []struct {
+		name string
+		old  string
+		new  string
+		want []string
+	}{
+		{
+			name: "unchanged",
+		},
+		{
+			name: "field added",
+			old:  "optional string title = 2;\n    Status status = 4;",
+			new:  "optional string title = 2;\n    Status status = 4;\n    int64 count = 5;",
+		},
+		{
+			name: "rpc removed",
+			old:  "    rpc DeleteTask(TaskPrimaryKey) returns (google.protobuf.Empty) {}\n",
+			new:  "",
+			want: []string{"t.proto: rpc TaskService.DeleteTask removed"},
+		},
+		{
+			name: "rpc request changed",
+			old:  "rpc GetByIDTask(TaskPrimaryKey)",
+			new:  "rpc GetByIDTask(GetTaskRequest)",
+			want: []string{"t.proto: rpc TaskService.GetByIDTask changed from (TaskPrimaryKey) returns (Task) to (GetTaskRequest) returns (Task)"},
+		},
+		{
+			name: "message renamed",
+			old:  "message TaskPrimaryKey {",
+			new:  "message TaskKey {",
+			want: []string{"t.proto: message TaskPrimaryKey renamed to TaskKey"},
+		},
+		{
+			name: "field renumbered",
+			old:  "optional string title = 2;\n    Status status = 4;",
+			new:  "optional string title = 4;\n    Status status = 2;",
+			want: []string{
+				"t.proto: message Task: field number 2 of title reused by status",
+				"t.proto: message Task: field status renumbered from 4 to 2",
+				"t.proto: message Task: field number 4 of status reused by title",
+				"t.proto: message Task: field title renumbered from 2 to 4",
+			},
+		},
+		{
+			name: "reserved number reused",
+			old:  "Status status = 4;",
+			new:  "Status status = 4;\n    string note = 3;",
+			want: []string{"t.proto: message Task: field note reuses reserved number 3"},
+		},
+		{
+			name: "type and label changed",
+			old:  "optional string title = 2;\n    Status status = 4;",
+			new:  "string title = 2;\n    string status = 4;",
+			want: []string{
+				"t.proto: message Task: field status changed type from Status to string",
+				"t.proto: message Task: field title changed from optional to required",
+			},
+		},
+		{
+			name: "enum value inserted by position",
+			old:  "    STATUS_DONE = 2;\n",
+			new:  "    STATUS_STARTED = 2;\n    STATUS_DONE = 3;\n",
+			want: []string{
+				"t.proto: enum Status: value STATUS_DONE renumbered from 2 to 3",
+				"t.proto: enum Status: value number 2 of STATUS_DONE reused by STATUS_STARTED",
+			},
+		},
+		{
+			name: "enum value inserted by the lock",
+			old:  "    STATUS_DONE = 2;\n",
+			new:  "    STATUS_STARTED = 3;\n    STATUS_DONE = 2;\n",
+		},
+		{
+			name: "enum removed",
+			old:  "enum Status {\n    STATUS_UNSPECIFIED = 0;\n    STATUS_NEW = 1;\n    STATUS_DONE = 2;\n}\n",
+			new:  "",
+			want: []string{"t.proto: enum Status removed"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var changed = replace(t, breakingBase, test.old, test.new)
+			if got := breakingChanges("t.proto", breakingBase, changed); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func replace(t *testing.T, s, old, new string) string {
+	t.Helper()
+
+	if !strings.Contains(s, old) {
+		t.Fatalf("%q not found", old)
+	}
+	return strings.Replace(s, old, new, 1)
+}
@@ -0,0 +1,253 @@
+package protos
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"githubc.com/asadbekGo/generate-code/config"
+	"githubc.com/asadbekGo/generate-code/pkg/writer"
+)
+
+// LockFile is the file, relative to the output root, that keeps the field
+// numbers of the generated messages. It is committed with the protos so a
+// column added or dropped in the middle of a table leaves the numbers of
+// the other fields alone.
+var LockFile = filepath.Join("protos", "fields.lock.json")
+
+// Lock maps every message, e.g. storehouse_client_service.Coming, to the
+// numbers of its fields and every enum to the numbers of its values.
+type Lock struct {
+	Messages map[string]*MessageLock `json:"messages"`
+}
+
+// MessageLock holds the field numbers of a message. Fields that are no
+// longer generated move to Reserved, their names to ReservedNames.
+type MessageLock struct {
+	Fields        map[string]int `json:"fields"`
+	Reserved      []int          `json:"reserved,omitempty"`
+	ReservedNames []string       `json:"reserved_names,omitempty"`
+}
+
+// fieldLock is loaded by LoadLock and updated by every rendered proto.
+var fieldLock = &Lock{Messages: map[string]*MessageLock{}}
+
+var (
+	messageStart = regexp.MustCompile(`^(\s*)message (\w+) \{\s*$`)
+	messageField = regexp.MustCompile(`^(\s*(?:optional |repeated )?[\w.]+ (\w+) = )(\d+)(;.*)$`)
+	enumStart    = regexp.MustCompile(`^(\s*)enum (\w+) \{\s*$`)
+	enumValue    = regexp.MustCompile(`^(\s*(\w+) = )(\d+)(;.*)$`)
+)
+
+// blockStart matches the first line of a message or an enum and returns
+// its indent, its name and the pattern of its fields or values.
+func blockStart(line string) (indent, name string, field *regexp.Regexp, ok bool) {
+	if match := messageStart.FindStringSubmatch(line); match != nil {
+		return match[1], match[2], messageField, true
+	}
+	if match := enumStart.FindStringSubmatch(line); match != nil {
+		return match[1], match[2], enumValue, true
+	}
+	return "", "", nil, false
+}
+
+// lockedField matches a field or value line of a block, the zero value
+// every enum starts with keeps its number and is not locked.
+func lockedField(field *regexp.Regexp, line string) []string {
+	var match = field.FindStringSubmatch(line)
+	if match == nil || (field == enumValue && match[3] == "0") {
+		return nil
+	}
+	return match
+}
+
+// LoadLock reads the lock file of the output root, a missing file starts
+// an empty lock.
+func LoadLock(cfg config.GenerateConfig) error {
+	fieldLock = &Lock{Messages: map[string]*MessageLock{}}
+
+	body, err := os.ReadFile(filepath.Join(cfg.OutputDir, LockFile))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	err = json.Unmarshal(body, fieldLock)
+	if err != nil {
+		return fmt.Errorf("%s: %w", LockFile, err)
+	}
+	if fieldLock.Messages == nil {
+		fieldLock.Messages = map[string]*MessageLock{}
+	}
+
+	return nil
+}
+
+// MakeLock writes the lock file with the numbers of this run.
+func MakeLock(w *writer.Writer) error {
+	body, err := json.MarshalIndent(fieldLock, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return w.WriteFile(LockFile, string(body)+"\n")
+}
+
+// seed locks the numbers of the messages and enums of an already
+// generated proto file that the lock does not know yet, so introducing the
+// lock keeps them.
+func (l *Lock) seed(protoPackage, text string) {
+	var (
+		message *MessageLock
+		field   *regexp.Regexp
+	)
+
+	for _, line := range strings.Split(text, "\n") {
+		if _, name, blockField, ok := blockStart(line); ok {
+			name, field = protoPackage+"."+name, blockField
+			if _, ok := l.Messages[name]; ok {
+				message = nil
+				continue
+			}
+			message = &MessageLock{Fields: map[string]int{}}
+			l.Messages[name] = message
+			continue
+		}
+
+		if message == nil {
+			continue
+		}
+		if match := lockedField(field, line); match != nil {
+			message.Fields[match[2]], _ = strconv.Atoi(match[3])
+		}
+	}
+}
+
+// apply renumbers the fields of every message and the values of every enum
+// in the rendered proto file with the locked numbers. New fields get the
+// next free number, fields the lock knows but the message no longer has
+// are reserved.
+func (l *Lock) apply(protoPackage, text string) string {
+	var (
+		out     []string
+		message []string
+		name    string
+		indent  string
+		field   *regexp.Regexp
+		used    map[string]bool
+	)
+
+	for _, line := range strings.Split(text, "\n") {
+		if blockIndent, blockName, blockField, ok := blockStart(line); ok {
+			name, indent, field, used = protoPackage+"."+blockName, blockIndent, blockField, map[string]bool{}
+			message = []string{line}
+			continue
+		}
+
+		if message == nil {
+			out = append(out, line)
+			continue
+		}
+
+		if strings.TrimSpace(line) == "}" {
+			out = append(out, message[0])
+			out = append(out, l.reserve(name, used, indent+"    ")...)
+			out = append(out, message[1:]...)
+			out = append(out, line)
+			message = nil
+			continue
+		}
+
+		if match := lockedField(field, line); match != nil {
+			used[match[2]] = true
+			line = match[1] + strconv.Itoa(l.number(name, match[2])) + match[4]
+		}
+		message = append(message, line)
+	}
+
+	return strings.Join(out, "\n")
+}
+
+// number returns the locked number of the field, assigning the next free
+// number to a field seen for the first time.
+func (l *Lock) number(name, field string) int {
+	var message = l.Messages[name]
+	if message == nil {
+		message = &MessageLock{Fields: map[string]int{}}
+		l.Messages[name] = message
+	}
+
+	if number, ok := message.Fields[field]; ok {
+		return number
+	}
+
+	var next = 1
+	for _, number := range message.Fields {
+		if number >= next {
+			next = number + 1
+		}
+	}
+	for _, number := range message.Reserved {
+		if number >= next {
+			next = number + 1
+		}
+	}
+
+	// a dropped field that comes back gets a new number, its name is
+	// free again
+	message.ReservedNames = remove(message.ReservedNames, field)
+	message.Fields[field] = next
+
+	return next
+}
+
+// reserve moves the fields of the message that were not used to the
+// reserved numbers and returns the reserved statements of the message.
+func (l *Lock) reserve(name string, used map[string]bool, indent string) []string {
+	var message = l.Messages[name]
+	if message == nil {
+		return nil
+	}
+
+	for field, number := range message.Fields {
+		if used[field] {
+			continue
+		}
+		delete(message.Fields, field)
+		message.Reserved = append(message.Reserved, number)
+		message.ReservedNames = append(message.ReservedNames, field)
+	}
+	sort.Ints(message.Reserved)
+	sort.Strings(message.ReservedNames)
+
+	var lines []string
+	if len(message.Reserved) > 0 {
+		var numbers []string
+		for _, number := range message.Reserved {
+			numbers = append(numbers, strconv.Itoa(number))
+		}
+		lines = append(lines, indent+"reserved "+strings.Join(numbers, ", ")+";")
+	}
+	if len(message.ReservedNames) > 0 {
+		lines = append(lines, indent+`reserved "`+strings.Join(message.ReservedNames, `", "`)+`";`)
+	}
+
+	return lines
+}
+
+func remove(s []string, e string) []string {
+	var out []string
+	for _, a := range s {
+		if a != e {
+			out = append(out, a)
+		}
+	}
+	return out
+}
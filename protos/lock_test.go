@@ -0,0 +1,119 @@
+package protos
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLockApply(t *testing.T) {
+	var tests = []struct {
+		name    string
+		seed    string
+		renders []string
+		want    string
+		lock    MessageLock
+	}{
+		{
+			name:    "first render keeps the numbers",
+			renders: []string{"message A {\n    string id = 1;\n    string name = 2;\n}"},
+			want:    "message A {\n    string id = 1;\n    string name = 2;\n}",
+			lock:    MessageLock{Fields: map[string]int{"id": 1, "name": 2}},
+		},
+		{
+			name: "column added in the middle",
+			renders: []string{
+				"message A {\n    string id = 1;\n    string name = 2;\n}",
+				"message A {\n    string id = 1;\n    string code = 2;\n    string name = 3;\n}",
+			},
+			want: "message A {\n    string id = 1;\n    string code = 3;\n    string name = 2;\n}",
+			lock: MessageLock{Fields: map[string]int{"id": 1, "name": 2, "code": 3}},
+		},
+		{
+			name: "column dropped",
+			renders: []string{
+				"message A {\n    string id = 1;\n    string code = 2;\n    string name = 3;\n}",
+				"message A {\n    string id = 1;\n    string name = 2;\n}",
+			},
+			want: "message A {\n    reserved 2;\n    reserved \"code\";\n    string id = 1;\n    string name = 3;\n}",
+			lock: MessageLock{Fields: map[string]int{"id": 1, "name": 3}, Reserved: []int{2}, ReservedNames: []string{"code"}},
+		},
+		{
+			name: "dropped column comes back",
+			renders: []string{
+				"message A {\n    string id = 1;\n    string code = 2;\n}",
+				"message A {\n    string id = 1;\n}",
+				"message A {\n    string id = 1;\n    optional int64 code = 2;\n}",
+			},
+			want: "message A {\n    reserved 2;\n    string id = 1;\n    optional int64 code = 3;\n}",
+			lock: MessageLock{Fields: map[string]int{"id": 1, "code": 3}, Reserved: []int{2}},
+		},
+		{
+			name:    "seeded from an existing file",
+			seed:    "message A {\n    string id = 1;\n    string name = 5;\n}",
+			renders: []string{"message A {\n    string id = 1;\n    string name = 2;\n    repeated string tags = 3;\n}"},
+			want:    "message A {\n    string id = 1;\n    string name = 5;\n    repeated string tags = 6;\n}",
+			lock:    MessageLock{Fields: map[string]int{"id": 1, "name": 5, "tags": 6}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				lock = &Lock{Messages: map[string]*MessageLock{}}
+				got  string
+			)
+
+			lock.seed("pkg", test.seed)
+			for _, render := range test.renders {
+				got = lock.apply("pkg", render)
+			}
+
+			if got != test.want {
+				t.Errorf("got\n%s\nwant\n%s", got, test.want)
+			}
+			if message := lock.Messages["pkg.A"]; !reflect.DeepEqual(*message, test.lock) {
+				t.Errorf("lock = %+v, want %+v", *message, test.lock)
+			}
+		})
+	}
+}
+
+func TestLockSeedKeepsLockedMessages(t *testing.T) {
+	var lock = &Lock{Messages: map[string]*MessageLock{
+		"pkg.A": {Fields: map[string]int{"id": 1, "name": 2}},
+	}}
+
+	lock.seed("pkg", "message A {\n    string id = 1;\n    string name = 7;\n}\n\nmessage B {\n    string id = 4;\n}")
+
+	if number := lock.Messages["pkg.A"].Fields["name"]; number != 2 {
+		t.Errorf("locked name = %d, want 2", number)
+	}
+	if number := lock.Messages["pkg.B"].Fields["id"]; number != 4 {
+		t.Errorf("seeded id = %d, want 4", number)
+	}
+}
+
+func TestLockEnum(t *testing.T) {
+	var lock = &Lock{Messages: map[string]*MessageLock{}}
+
+	var renders = []string{
+		"enum Status {\n    STATUS_UNSPECIFIED = 0;\n    STATUS_NEW = 1;\n    STATUS_DONE = 2;\n}",
+		"enum Status {\n    STATUS_UNSPECIFIED = 0;\n    STATUS_NEW = 1;\n    STATUS_STARTED = 2;\n    STATUS_DONE = 3;\n}",
+		"enum Status {\n    STATUS_UNSPECIFIED = 0;\n    STATUS_STARTED = 1;\n    STATUS_DONE = 2;\n}",
+	}
+	var want = []string{
+		renders[0],
+		"enum Status {\n    STATUS_UNSPECIFIED = 0;\n    STATUS_NEW = 1;\n    STATUS_STARTED = 3;\n    STATUS_DONE = 2;\n}",
+		"enum Status {\n    reserved 1;\n    reserved \"STATUS_NEW\";\n    STATUS_UNSPECIFIED = 0;\n    STATUS_STARTED = 3;\n    STATUS_DONE = 2;\n}",
+	}
+
+	for i, render := range renders {
+		var got = lock.apply("pkg", render)
+		if got != want[i] {
+			t.Errorf("render %d: got\n%s\nwant\n%s", i, got, want[i])
+		}
+		if changes := breakingChanges("status.proto", renders[0], got); i < 2 && len(changes) > 0 {
+			t.Errorf("render %d: breaking changes %q", i, changes)
+		}
+	}
+}
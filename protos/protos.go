@@ -3,64 +3,233 @@ package protos
 import (
 	"fmt"
 	"log"
+	"os"
+	"path/filepath"
 	"strings"
 
+	"githubc.com/asadbekGo/generate-code/config"
 	"githubc.com/asadbekGo/generate-code/pkg/helper"
+	"githubc.com/asadbekGo/generate-code/pkg/writer"
+	"githubc.com/asadbekGo/generate-code/schema"
 )
 
-func MakeProtos(sqlBody []byte) error {
+// enumPackages is the proto package each enum file was written for, an
+// enum shared by several tables is generated once.
+var enumPackages = map[string]string{}
 
-	var sqlTable = helper.RemoveEmptyRows(string(sqlBody))
-	if len(sqlTable) <= 0 {
-		return nil
+// commonPackage is the proto package common.proto was written for.
+var commonPackage string
+
+// serviceProtos collects the table protos of every proto package for
+// config.ProtoLayoutService, AddServiceProto adds them and
+// MakeServiceProtos writes each package as one file.
+var (
+	serviceProtos   = map[string][]string{}
+	servicePackages []string
+)
+
+func MakeProtos(cfg config.GenerateConfig, w *writer.Writer, s *schema.Schema, table *schema.Table) error {
+
+	var data = helper.NewTemplateData(cfg.Project, s, table)
+
+	// the package protos of config.ProtoLayoutService are collected by
+	// AddServiceProto
+	if cfg.Project.ProtoLayout != config.ProtoLayoutService {
+		filename, templateProto, err := renderProto(cfg, data)
+		if err != nil {
+			return err
+		}
+
+		err = w.WriteFile(filename, templateProto)
+		if err != nil {
+			log.Println("Error while WriteFile:", err.Error())
+			return err
+		}
 	}
 
-	tableName, fields, err := helper.ParseSQLQuery(sqlTable)
+	err := makeCommon(cfg, w, data)
 	if err != nil {
-		log.Println("Error while ParseSQLQuery:", err.Error())
 		return err
 	}
 
-	for index, field := range fields {
-		var fieldType = strings.Split(field, ":")
-		fields[index] = fieldType[0] + ":" + helper.SQLToGoType(fieldType[1])
+	return makeEnums(cfg, w, data)
+}
+
+// AddServiceProto adds the proto of the table to the file of its proto
+// package for config.ProtoLayoutService. Every table of the schema is
+// added, not only the ones selected with -tables.
+func AddServiceProto(cfg config.GenerateConfig, s *schema.Schema, table *schema.Table) error {
+
+	if cfg.Project.ProtoLayout != config.ProtoLayoutService {
+		return nil
 	}
 
-	var templateProtoFilename = "./protos/template.proto"
-	templateProtoBody, err := helper.ReadFile(templateProtoFilename)
+	var data = helper.NewTemplateData(cfg.Project, s, table)
+
+	_, templateProto, err := renderProto(cfg, data)
 	if err != nil {
-		log.Println("Error while ReadFile:", err.Error())
 		return err
 	}
 
+	var protoPackage = data.Group.ProtoPackage
+	if _, ok := serviceProtos[protoPackage]; !ok {
+		servicePackages = append(servicePackages, protoPackage)
+	}
+	serviceProtos[protoPackage] = append(serviceProtos[protoPackage], templateProto)
+
+	return nil
+}
+
+// MakeServiceProtos writes the proto file of every proto package collected
+// by MakeProtos for config.ProtoLayoutService, e.g.
+// protos/storehouse_client_service.proto.
+func MakeServiceProtos(w *writer.Writer) error {
+
+	for _, protoPackage := range servicePackages {
+		err := w.WriteFile(filepath.Join("protos", protoPackage+".proto"), mergeProtos(serviceProtos[protoPackage]))
+		if err != nil {
+			log.Println("Error while WriteFile:", err.Error())
+			return err
+		}
+	}
+
+	return nil
+}
+
+// BreakingChanges compares the proto files of the tables selected with
+// -tables, their enums and common.proto with the files they would replace
+// and describes the changes that break deployed clients. The package protos
+// of config.ProtoLayoutService hold every table, selected or not.
+func BreakingChanges(cfg config.GenerateConfig, s *schema.Schema, tables []*schema.Table) ([]string, error) {
+
 	var (
-		templateProto      = string(templateProtoBody)
-		camelCaseText      = helper.SnakeToCamel(tableName)
-		upperHeadTableName = strings.ToUpper(string(camelCaseText[0])) + camelCaseText[1:]
+		files     = map[string][]string{}
+		filenames []string
+		changes   []string
 	)
 
-	templateProto = strings.ReplaceAll(templateProto, "message Template {}", GenerateProtoMessage(upperHeadTableName, fields))
+	var add = func(filename, templateProto string) {
+		if _, ok := files[filename]; !ok {
+			filenames = append(filenames, filename)
+		}
+		files[filename] = append(files[filename], templateProto)
+	}
+
+	for _, table := range tables {
+		var (
+			data     = helper.NewTemplateData(cfg.Project, s, table)
+			selected = cfg.Selected(table.Name)
+		)
+
+		if !selected && cfg.Project.ProtoLayout != config.ProtoLayoutService {
+			continue
+		}
+
+		filename, templateProto, err := renderProto(cfg, data)
+		if err != nil {
+			return nil, err
+		}
+		add(filename, templateProto)
+
+		if !selected {
+			continue
+		}
+
+		if cfg.Project.CommonProto {
+			filename, templateProto, err = renderCommon(cfg, data)
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := files[filename]; !ok {
+				add(filename, templateProto)
+			}
+		}
+
+		for _, enum := range data.Enums() {
+			filename, templateProto, err = renderEnum(cfg, data, enum)
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := files[filename]; !ok {
+				add(filename, templateProto)
+			}
+		}
+	}
+
+	for _, filename := range filenames {
+		current, err := os.ReadFile(filepath.Join(cfg.OutputDir, filename))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		changes = append(changes, breakingChanges(filepath.ToSlash(filename), string(current), mergeProtos(files[filename]))...)
+	}
+
+	return changes, nil
+}
+
+// mergeProtos joins rendered proto files of one proto package, the header
+// of the first file is followed by the imports of all files and their
+// services and messages.
+func mergeProtos(protos []string) string {
+	if len(protos) == 1 {
+		return protos[0]
+	}
 
 	var (
-		createFields []string
-		updateFields []string
+		header  []string
+		imports []string
+		bodies  []string
 	)
-	for _, field := range fields {
-		if field != "id:string" && !strings.Contains(field, "created_at:string") && !strings.Contains(field, "updated_at:string") {
-			createFields = append(createFields, field)
+
+	for i, proto := range protos {
+		var lines = strings.Split(strings.TrimRight(proto, "\n"), "\n")
+		for j, line := range lines {
+			if strings.HasPrefix(line, "service ") || strings.HasPrefix(line, "message ") {
+				bodies = append(bodies, strings.Join(lines[j:], "\n"))
+				break
+			}
+
+			switch {
+			case strings.HasPrefix(line, "import "):
+				if !contains(imports, line) {
+					imports = append(imports, line)
+				}
+			case i == 0 && strings.TrimSpace(line) != "":
+				header = append(header, line)
+			}
 		}
+	}
+
+	return header[0] + "\n\n" + strings.Join(header[1:], "\n") + "\n\n" +
+		strings.Join(imports, "\n") + "\n\n" + strings.Join(bodies, "\n\n") + "\n"
+}
+
+// makeCommon writes common.proto for config.Project.CommonProto, once for
+// the single proto package using it.
+func makeCommon(cfg config.GenerateConfig, w *writer.Writer, data helper.TemplateData) error {
 
-		if !strings.Contains(field, "created_at:string") && !strings.Contains(field, "updated_at:string") {
-			updateFields = append(updateFields, field)
+	if !cfg.Project.CommonProto {
+		return nil
+	}
+
+	if commonPackage != "" {
+		if commonPackage != data.Group.ProtoPackage {
+			return fmt.Errorf("common.proto is used by tables of the proto packages %s and %s", commonPackage, data.Group.ProtoPackage)
 		}
+		return nil
 	}
+	commonPackage = data.Group.ProtoPackage
 
-	templateProto = strings.ReplaceAll(templateProto, "message CreateTemplateRequest {}", GenerateProtoMessage(fmt.Sprintf("Create%sRequest", upperHeadTableName), createFields))
-	templateProto = strings.ReplaceAll(templateProto, "message UpdateTemplateRequest {}", GenerateProtoMessage(fmt.Sprintf("Update%sRequest", upperHeadTableName), updateFields))
-	templateProto = strings.ReplaceAll(templateProto, "Template", upperHeadTableName)
-	templateProto = strings.ReplaceAll(templateProto, "templates", helper.Pluralize(tableName))
+	filename, templateProto, err := renderCommon(cfg, data)
+	if err != nil {
+		return err
+	}
 
-	err = helper.WriteFile("./generates/protos/"+tableName+".proto", templateProto)
+	err = w.WriteFile(filename, templateProto)
 	if err != nil {
 		log.Println("Error while WriteFile:", err.Error())
 		return err
@@ -69,22 +238,106 @@ func MakeProtos(sqlBody []byte) error {
 	return nil
 }
 
-func GenerateProtoMessage(messageName string, fields []string) string {
-	var text = fmt.Sprintf("message %s {\n", messageName)
-	for fieldIndex, field := range fields {
-		parts := strings.Split(strings.TrimSpace(field), ":")
-		if len(parts) == 2 {
-			fieldName := strings.TrimSpace(parts[0])
-			fieldType := strings.TrimSpace(parts[1])
+// renderCommon renders common.proto numbered by the lock.
+func renderCommon(cfg config.GenerateConfig, data helper.TemplateData) (string, string, error) {
+
+	templateProto, err := helper.RenderTemplate(filepath.Join(cfg.TemplateDir, "protos", "common.proto"), data)
+	if err != nil {
+		log.Println("Error while RenderTemplate:", err.Error())
+		return "", "", err
+	}
+
+	var filename = filepath.Join("protos", "common.proto")
+	return filename, lockProto(cfg, data.Group.ProtoPackage, filename, templateProto), nil
+}
+
+// renderEnum renders the proto file of the enum numbered by the lock, a
+// value added in the middle of the type keeps the numbers of the others.
+func renderEnum(cfg config.GenerateConfig, data helper.TemplateData, enum *schema.Enum) (string, string, error) {
+
+	data.Enum = enum
+	templateProto, err := helper.RenderTemplate(filepath.Join(cfg.TemplateDir, "protos", "enum.proto"), data)
+	if err != nil {
+		log.Println("Error while RenderTemplate:", err.Error())
+		return "", "", err
+	}
+
+	var filename = filepath.Join("protos", enum.Name+".proto")
+	return filename, lockProto(cfg, data.Group.ProtoPackage, filename, templateProto), nil
+}
 
-			if fieldType == "interface{}" {
-				fieldType = "string"
+// lockProto numbers the rendered proto file by the lock, seeded with the
+// numbers of the file it replaces.
+func lockProto(cfg config.GenerateConfig, protoPackage, filename, templateProto string) string {
+	current, err := os.ReadFile(filepath.Join(cfg.OutputDir, filename))
+	if err == nil {
+		fieldLock.seed(protoPackage, string(current))
+	}
+
+	return fieldLock.apply(protoPackage, templateProto)
+}
+
+// renderProto renders the proto file of the table numbered by the lock and
+// returns the file it is written to, the file of its proto package with
+// config.ProtoLayoutService.
+func renderProto(cfg config.GenerateConfig, data helper.TemplateData) (string, string, error) {
+
+	var name = "template.proto"
+
+	sides, err := data.Join()
+	if err != nil {
+		log.Println("Error while Join:", err.Error())
+		return "", "", err
+	}
+	if sides != nil {
+		name = "join.proto"
+	}
+
+	templateProto, err := helper.RenderTemplate(filepath.Join(cfg.TemplateDir, "protos", name), data)
+	if err != nil {
+		log.Println("Error while RenderTemplate:", err.Error())
+		return "", "", err
+	}
+
+	var filename = filepath.Join("protos", data.Table.Name+".proto")
+	if cfg.Project.ProtoLayout == config.ProtoLayoutService {
+		filename = filepath.Join("protos", data.Group.ProtoPackage+".proto")
+	}
+
+	return filename, lockProto(cfg, data.Group.ProtoPackage, filename, templateProto), nil
+}
+
+func makeEnums(cfg config.GenerateConfig, w *writer.Writer, data helper.TemplateData) error {
+
+	for _, enum := range data.Enums() {
+		if protoPackage, ok := enumPackages[enum.Name]; ok {
+			if protoPackage != data.Group.ProtoPackage {
+				return fmt.Errorf("enum %s is used by tables of the proto packages %s and %s", enum.Name, protoPackage, data.Group.ProtoPackage)
 			}
+			continue
+		}
+		enumPackages[enum.Name] = data.Group.ProtoPackage
 
-			text += fmt.Sprintf("    %s %s = %d;\n", fieldType, fieldName, fieldIndex+1)
+		filename, templateProto, err := renderEnum(cfg, data, enum)
+		if err != nil {
+			return err
+		}
+
+		err = w.WriteFile(filename, templateProto)
+		if err != nil {
+			log.Println("Error while WriteFile:", err.Error())
+			return err
 		}
 	}
-	text += "}"
 
-	return text
+	return nil
+}
+
+func contains(s []string, e string) bool {
+	for _, a := range s {
+		if a == e {
+			return true
+		}
+	}
+	return false
 }
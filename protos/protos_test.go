@@ -0,0 +1,65 @@
+package protos
+
+import "testing"
+
+func TestMergeProtos(t *testing.T) {
+	var protos = []string{
+		`syntax="proto3";
+
+package pkg;
+option go_package="genproto/pkg";
+
+import "google/protobuf/empty.proto";
+import "common.proto";
+
+service AService {
+    rpc GetA(APrimaryKey) returns (A) {}
+}
+
+message A {
+    string id = 1;
+}
+`,
+		`syntax="proto3";
+
+package pkg;
+option go_package="genproto/pkg";
+
+import "google/protobuf/struct.proto";
+import "common.proto";
+
+message B {
+    string id = 1;
+}
+`,
+	}
+
+	const want = `syntax="proto3";
+
+package pkg;
+option go_package="genproto/pkg";
+
+import "google/protobuf/empty.proto";
+import "common.proto";
+import "google/protobuf/struct.proto";
+
+service AService {
+    rpc GetA(APrimaryKey) returns (A) {}
+}
+
+message A {
+    string id = 1;
+}
+
+message B {
+    string id = 1;
+}
+`
+
+	if got := mergeProtos(protos); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+	if got := mergeProtos(protos[:1]); got != protos[0] {
+		t.Errorf("a single file changed:\n%s", got)
+	}
+}
@@ -0,0 +1,197 @@
+package schema
+
+// DropTable removes the table and the foreign keys of other tables pointing at it.
+func (s *Schema) DropTable(name string) {
+	var tables []*Table
+	for _, table := range s.Tables {
+		if table.Name != name {
+			tables = append(tables, table)
+		}
+	}
+	s.Tables = tables
+
+	for _, table := range s.Tables {
+		var foreignKeys []*ForeignKey
+		for _, foreignKey := range table.ForeignKeys {
+			if foreignKey.RefTable != name {
+				foreignKeys = append(foreignKeys, foreignKey)
+				continue
+			}
+			for _, column := range table.Columns {
+				if column.References == foreignKey {
+					column.References = nil
+				}
+			}
+		}
+		table.ForeignKeys = foreignKeys
+	}
+}
+
+// RenameTable renames the table and every foreign key pointing at it.
+func (s *Schema) RenameTable(from, to string) {
+	for _, table := range s.Tables {
+		if table.Name == from {
+			table.Name = to
+		}
+		for _, foreignKey := range table.ForeignKeys {
+			if foreignKey.RefTable == from {
+				foreignKey.RefTable = to
+			}
+		}
+	}
+}
+
+// RenameColumn renames a column of the table in the table itself, in its
+// constraints and indexes and in the foreign keys of other tables.
+func (s *Schema) RenameColumn(table *Table, from, to string) {
+	if column := table.Column(from); column != nil {
+		column.Name = to
+	}
+
+	renameIn(table.PrimaryKey, from, to)
+	for _, index := range table.Indexes {
+		renameIn(index.Columns, from, to)
+	}
+	for _, foreignKey := range table.ForeignKeys {
+		renameIn(foreignKey.Columns, from, to)
+	}
+
+	for _, other := range s.Tables {
+		for _, foreignKey := range other.ForeignKeys {
+			if foreignKey.RefTable == table.Name {
+				renameIn(foreignKey.RefColumns, from, to)
+			}
+		}
+	}
+}
+
+// DropColumn removes the column together with the primary key, indexes and
+// foreign keys it was part of, as Postgres does. The column flags are left to
+// Resolve.
+func (t *Table) DropColumn(name string) {
+	var columns []*Column
+	for _, column := range t.Columns {
+		if column.Name != name {
+			columns = append(columns, column)
+		}
+	}
+	t.Columns = columns
+
+	if contains(t.PrimaryKey, name) {
+		t.PrimaryKey, t.PrimaryKeyName = nil, ""
+	}
+
+	var indexes []*Index
+	for _, index := range t.Indexes {
+		if !contains(index.Columns, name) {
+			indexes = append(indexes, index)
+		}
+	}
+	t.Indexes = indexes
+
+	var foreignKeys []*ForeignKey
+	for _, foreignKey := range t.ForeignKeys {
+		if !contains(foreignKey.Columns, name) {
+			foreignKeys = append(foreignKeys, foreignKey)
+		}
+	}
+	t.ForeignKeys = foreignKeys
+}
+
+// DropConstraint removes the named primary key, unique constraint, foreign
+// key or check. It reports whether a constraint was found. The column flags
+// are left to Resolve.
+func (t *Table) DropConstraint(name string) bool {
+	if name == t.PrimaryKeyName && len(t.PrimaryKey) > 0 {
+		t.PrimaryKey, t.PrimaryKeyName = nil, ""
+		return true
+	}
+
+	for i, index := range t.Indexes {
+		if index.Name == name {
+			t.Indexes = append(t.Indexes[:i], t.Indexes[i+1:]...)
+			return true
+		}
+	}
+
+	for i, foreignKey := range t.ForeignKeys {
+		if foreignKey.Name == name {
+			t.ForeignKeys = append(t.ForeignKeys[:i], t.ForeignKeys[i+1:]...)
+			return true
+		}
+	}
+
+	for i, check := range t.Checks {
+		if check.Name == name {
+			t.Checks = append(t.Checks[:i], t.Checks[i+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
+
+// RenameConstraint renames the named primary key, unique constraint,
+// foreign key or check.
+func (t *Table) RenameConstraint(from, to string) {
+	if t.PrimaryKeyName == from {
+		t.PrimaryKeyName = to
+	}
+	for _, index := range t.Indexes {
+		if index.Name == from {
+			index.Name = to
+		}
+	}
+	for _, foreignKey := range t.ForeignKeys {
+		if foreignKey.Name == from {
+			foreignKey.Name = to
+		}
+	}
+	for _, check := range t.Checks {
+		if check.Name == from {
+			check.Name = to
+		}
+	}
+}
+
+// DropIndex removes the named index from whichever table has it and returns
+// that table, or nil if no table has the index.
+func (s *Schema) DropIndex(name string) *Table {
+	for _, table := range s.Tables {
+		for i, index := range table.Indexes {
+			if index.Name == name {
+				table.Indexes = append(table.Indexes[:i], table.Indexes[i+1:]...)
+				return table
+			}
+		}
+	}
+	return nil
+}
+
+// DropEnum removes the enum type with the given name.
+func (s *Schema) DropEnum(name string) {
+	var enums []*Enum
+	for _, enum := range s.Enums {
+		if enum.Name != name {
+			enums = append(enums, enum)
+		}
+	}
+	s.Enums = enums
+}
+
+func renameIn(names []string, from, to string) {
+	for i, name := range names {
+		if name == from {
+			names[i] = to
+		}
+	}
+}
+
+func contains(s []string, e string) bool {
+	for _, a := range s {
+		if a == e {
+			return true
+		}
+	}
+	return false
+}
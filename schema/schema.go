@@ -0,0 +1,289 @@
+package schema
+
+import "fmt"
+
+// Schema is every table and type read from the input.
+type Schema struct {
+	Tables []*Table
+	Enums  []*Enum
+}
+
+// Table is a parsed CREATE TABLE statement.
+type Table struct {
+	Name       string
+	Columns    []*Column
+	PrimaryKey []string
+	// PrimaryKeyName is the name of the primary key constraint, by default
+	// <table>_pkey.
+	PrimaryKeyName string
+	ForeignKeys    []*ForeignKey
+	Indexes        []*Index
+	Checks         []*Check
+	// Comment is the -- comment above CREATE TABLE or COMMENT ON TABLE.
+	Comment string
+}
+
+// Column is a single column of a table. Type is the lower cased SQL type
+// without length or precision, e.g. "varchar" or "numeric".
+type Column struct {
+	Name       string
+	Type       string
+	Length     int
+	Precision  int
+	Scale      int
+	Array      bool
+	NotNull    bool
+	HasDefault bool
+	Default    string
+	Identity   bool
+	Generated  bool
+	PrimaryKey bool
+	Unique     bool
+	References *ForeignKey
+	// Comment is the -- comment trailing or above the column definition or
+	// COMMENT ON COLUMN.
+	Comment string
+}
+
+// ForeignKey is a REFERENCES clause, either on a column or on the table.
+type ForeignKey struct {
+	Name       string
+	Columns    []string
+	RefTable   string
+	RefColumns []string
+	OnDelete   string
+	OnUpdate   string
+}
+
+// Index is a UNIQUE constraint or a CREATE INDEX statement. Columns leaves
+// out the expressions of an expression index, Expression tells it apart.
+// Predicate is the WHERE clause of a partial index.
+type Index struct {
+	Name       string
+	Columns    []string
+	Unique     bool
+	Expression bool
+	Predicate  string
+}
+
+// Check is a CHECK constraint, Expression is the condition inside its
+// parentheses.
+type Check struct {
+	Name       string
+	Expression string
+}
+
+// Enum is a CREATE TYPE ... AS ENUM statement.
+type Enum struct {
+	Name   string
+	Values []string
+}
+
+// Table returns the table with the given name or nil.
+func (s *Schema) Table(name string) *Table {
+	for _, table := range s.Tables {
+		if table.Name == name {
+			return table
+		}
+	}
+	return nil
+}
+
+// Enum returns the enum type with the given name or nil.
+func (s *Schema) Enum(name string) *Enum {
+	for _, enum := range s.Enums {
+		if enum.Name == name {
+			return enum
+		}
+	}
+	return nil
+}
+
+// Column returns the column with the given name or nil.
+func (t *Table) Column(name string) *Column {
+	for _, column := range t.Columns {
+		if column.Name == name {
+			return column
+		}
+	}
+	return nil
+}
+
+// HasColumn reports whether the table has a column with the given name.
+func (t *Table) HasColumn(name string) bool {
+	return t.Column(name) != nil
+}
+
+// KeyColumns returns the primary key columns, a table without a primary
+// key falls back to its id column.
+func (t *Table) KeyColumns() []*Column {
+	var columns []*Column
+	for _, name := range t.PrimaryKey {
+		if column := t.Column(name); column != nil {
+			columns = append(columns, column)
+		}
+	}
+
+	if len(columns) == 0 {
+		if id := t.Column("id"); id != nil {
+			columns = append(columns, id)
+		}
+	}
+
+	return columns
+}
+
+// Fields returns the columns carrying data, that is every column except
+// the key and the created_at/updated_at timestamps the templates manage.
+func (t *Table) Fields() []*Column {
+	var (
+		key    = t.KeyColumns()
+		fields []*Column
+	)
+
+	for _, column := range t.Columns {
+		if column.Name == "created_at" || column.Name == "updated_at" || containsColumn(key, column) {
+			continue
+		}
+		fields = append(fields, column)
+	}
+	return fields
+}
+
+// ReadColumns returns the key columns followed by the fields, in the order
+// they are selected.
+func (t *Table) ReadColumns() []*Column {
+	return append(t.KeyColumns(), t.Fields()...)
+}
+
+// WritableFields returns the fields Create and Update write, identity and
+// generated columns are left to the database.
+func (t *Table) WritableFields() []*Column {
+	var fields []*Column
+	for _, column := range t.Fields() {
+		if column.Identity || column.Generated {
+			continue
+		}
+		fields = append(fields, column)
+	}
+	return fields
+}
+
+// Nullable reports whether the column accepts NULL.
+func (c *Column) Nullable() bool {
+	return !c.NotNull && !c.PrimaryKey
+}
+
+// Resolve marks the primary key and unique columns and links single column
+// foreign keys to their column once every column of the table is known. The
+// flags are recomputed from scratch, so it is also run after every DROP.
+func (t *Table) Resolve() error {
+	for _, column := range t.Columns {
+		column.PrimaryKey, column.Unique, column.References = false, false, nil
+	}
+
+	for _, name := range t.PrimaryKey {
+		column := t.Column(name)
+		if column == nil {
+			return fmt.Errorf("table %s: primary key column %q does not exist", t.Name, name)
+		}
+		column.PrimaryKey = true
+		column.NotNull = true
+	}
+
+	for _, foreignKey := range t.ForeignKeys {
+		if len(foreignKey.Columns) == 1 {
+			if column := t.Column(foreignKey.Columns[0]); column != nil {
+				column.References = foreignKey
+			}
+		}
+	}
+
+	for _, index := range t.Indexes {
+		if index.Unique && !index.Expression && index.Predicate == "" && len(index.Columns) == 1 {
+			if column := t.Column(index.Columns[0]); column != nil {
+				column.Unique = true
+			}
+		}
+	}
+
+	return nil
+}
+
+// Indexed reports whether the primary key or an index starts with the
+// columns, in any order.
+func (t *Table) Indexed(columns []string) bool {
+	if hasPrefix(t.PrimaryKey, columns) {
+		return true
+	}
+	for _, index := range t.Indexes {
+		if hasPrefix(index.Columns, columns) {
+			return true
+		}
+	}
+	return false
+}
+
+// UnindexedForeignKeys returns the foreign keys no index covers, deleting
+// or updating a referenced row scans the whole table for them.
+func (t *Table) UnindexedForeignKeys() []*ForeignKey {
+	var foreignKeys []*ForeignKey
+	for _, foreignKey := range t.ForeignKeys {
+		if !t.Indexed(foreignKey.Columns) {
+			foreignKeys = append(foreignKeys, foreignKey)
+		}
+	}
+	return foreignKeys
+}
+
+func hasPrefix(indexed, columns []string) bool {
+	if len(columns) == 0 || len(indexed) < len(columns) {
+		return false
+	}
+	for _, name := range columns {
+		if !contains(indexed[:len(columns)], name) {
+			return false
+		}
+	}
+	return true
+}
+
+func containsColumn(columns []*Column, column *Column) bool {
+	for _, c := range columns {
+		if c == column {
+			return true
+		}
+	}
+	return false
+}
+
+// typeAliases maps the spellings Postgres accepts to the name it reports.
+var typeAliases = map[string]string{
+	"int":                         "integer",
+	"int4":                        "integer",
+	"int2":                        "smallint",
+	"int8":                        "bigint",
+	"serial4":                     "serial",
+	"serial2":                     "smallserial",
+	"serial8":                     "bigserial",
+	"float4":                      "real",
+	"float8":                      "double precision",
+	"bool":                        "boolean",
+	"decimal":                     "numeric",
+	"character varying":           "varchar",
+	"character":                   "char",
+	"bpchar":                      "char",
+	"timestamp without time zone": "timestamp",
+	"timestamp with time zone":    "timestamptz",
+	"time without time zone":      "time",
+	"time with time zone":         "timetz",
+	"bit varying":                 "varbit",
+}
+
+// NormalizeType returns the canonical lower cased name of a SQL type.
+func NormalizeType(name string) string {
+	if alias, ok := typeAliases[name]; ok {
+		return alias
+	}
+	return name
+}
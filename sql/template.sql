@@ -1,3 +1,5 @@
+CREATE TYPE status_transaction AS ENUM ('coming', 'return', 'other');
+
 
 CREATE TABLE IF NOT EXISTS "coming" (
     "id" UUID NOT NULL DEFAULT uuid_generate_v4() PRIMARY KEY,
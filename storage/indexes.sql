@@ -0,0 +1,6 @@
+-- Suggested indexes for the foreign keys no index covers. Without them
+-- listing rows by parent and deleting a parent row scan the whole table.
+{{- range . }}
+
+CREATE INDEX IF NOT EXISTS "{{ .Name }}" ON "{{ .Table }}" ({{ .Columns }});
+{{- end }}
@@ -1,50 +1,58 @@
 package storage
 
 import (
-	"fmt"
 	"log"
+	"path/filepath"
+	"sort"
 	"strings"
 
+	"githubc.com/asadbekGo/generate-code/config"
 	"githubc.com/asadbekGo/generate-code/pkg/helper"
+	"githubc.com/asadbekGo/generate-code/pkg/writer"
+	"githubc.com/asadbekGo/generate-code/schema"
 )
 
-var storageRepoTexts string
+// storageRepoTexts are the repo interfaces of the tables by storage
+// package, every group gets its own storage.go.
+var storageRepoTexts = map[string]string{}
 
-func MakeService(sqlBody []byte) error {
+// foreignKeyIndexes are the indexes suggested for the foreign keys of the
+// generated tables.
+var foreignKeyIndexes []foreignKeyIndex
 
-	var sqlTable = helper.RemoveEmptyRows(string(sqlBody))
-	if len(sqlTable) <= 0 {
-		return nil
-	}
+type foreignKeyIndex struct {
+	Name    string
+	Table   string
+	Columns string
+}
+
+// enumFiles are the enum files already written, an enum shared by several
+// tables of a storage package is generated once per package.
+var enumFiles = map[string]bool{}
 
-	tableName, fields, err := helper.ParseSQLQuery(sqlTable)
+func MakeService(cfg config.GenerateConfig, w *writer.Writer, s *schema.Schema, table *schema.Table) error {
+
+	var (
+		data = helper.NewTemplateData(cfg.Project, s, table)
+		name = "template_service.txt"
+	)
+
+	sides, err := data.Join()
 	if err != nil {
-		log.Println("Error while ParseSQLQuery:", err.Error())
+		log.Println("Error while Join:", err.Error())
 		return err
 	}
-
-	for index, field := range fields {
-		var fieldType = strings.Split(field, ":")
-		fields[index] = fieldType[0] + ":" + helper.SQLToGoType(fieldType[1])
+	if sides != nil {
+		name = "join_service.txt"
 	}
 
-	var templateGoFilename = "./storage/template_service.txt"
-	templateGoBody, err := helper.ReadFile(templateGoFilename)
+	templateGo, err := helper.RenderTemplate(filepath.Join(cfg.TemplateDir, "storage", name), data)
 	if err != nil {
-		log.Println("Error while ReadFile:", err.Error())
+		log.Println("Error while RenderTemplate:", err.Error())
 		return err
 	}
 
-	var (
-		templateGo         = string(templateGoBody)
-		camelCaseText      = helper.SnakeToCamel(tableName)
-		upperHeadTableName = strings.ToUpper(string(camelCaseText[0])) + camelCaseText[1:]
-	)
-
-	templateGo = strings.ReplaceAll(templateGo, "Template", upperHeadTableName)
-	templateGo = strings.ReplaceAll(templateGo, "template", tableName)
-
-	err = helper.WriteFile("generates/service/"+tableName+".go", templateGo)
+	err = w.WriteFile(filepath.Join("service", data.Group.ServicePackage, table.Name+".go"), templateGo)
 	if err != nil {
 		log.Println("Error while WriteFile:", err.Error())
 		return err
@@ -53,141 +61,134 @@ func MakeService(sqlBody []byte) error {
 	return nil
 }
 
-func MakeStorage(sqlBody []byte) error {
+func MakeStorage(cfg config.GenerateConfig, w *writer.Writer, s *schema.Schema, table *schema.Table) error {
 
-	var sqlTable = helper.RemoveEmptyRows(string(sqlBody))
-	if len(sqlTable) <= 0 {
-		return nil
+	var (
+		data = helper.NewTemplateData(cfg.Project, s, table)
+		name = "template_storage.txt"
+	)
+
+	sides, err := data.Join()
+	if err != nil {
+		log.Println("Error while Join:", err.Error())
+		return err
+	}
+	if sides != nil {
+		name = "join_storage.txt"
 	}
 
-	tableName, fields, err := helper.ParseSQLQuery(sqlTable)
+	templateGo, err := helper.RenderTemplate(filepath.Join(cfg.TemplateDir, "storage", name), data)
 	if err != nil {
-		log.Println("Error while ParseSQLQuery:", err.Error())
+		log.Println("Error while RenderTemplate:", err.Error())
 		return err
 	}
 
-	var templateGoFilename = "./storage/template_storage.txt"
-	templateGoBody, err := helper.ReadFile(templateGoFilename)
+	err = w.WriteFile(filepath.Join("storage", data.Group.StoragePackage, table.Name+".go"), templateGo)
 	if err != nil {
-		log.Println("Error while ReadFile:", err.Error())
+		log.Println("Error while WriteFile:", err.Error())
 		return err
 	}
 
-	var (
-		templateGo         = string(templateGoBody)
-		camelCaseText      = helper.SnakeToCamel(tableName)
-		upperHeadTableName = strings.ToUpper(string(camelCaseText[0])) + camelCaseText[1:]
-	)
+	return makeEnums(cfg, w, data)
+}
 
-	templateGo = strings.ReplaceAll(templateGo, "Template", upperHeadTableName)
-	templateGo = strings.ReplaceAll(templateGo, "template", tableName)
+// AddStorageRepo adds the repo interface of the table to the storage.go of
+// its package and its unindexed foreign keys to the index migration. Every
+// table of the schema is added, not only the ones selected with -tables.
+func AddStorageRepo(cfg config.GenerateConfig, s *schema.Schema, table *schema.Table) error {
 
-	var query = generateQuery(tableName, fields)
-	templateGo = strings.ReplaceAll(templateGo, "insertQuery", query.InsertQuery)
-	templateGo = strings.ReplaceAll(templateGo, "insertExecField", query.InsertExecField)
-	templateGo = strings.ReplaceAll(templateGo, "getQuery", query.GetQuery)
-	templateGo = strings.ReplaceAll(templateGo, "updateQuery", query.UpdateQuery)
-	templateGo = strings.ReplaceAll(templateGo, "updateExecQuery", query.UpdateExecQuery)
-	templateGo = strings.ReplaceAll(templateGo, "varNullString", query.VarNullString)
-	templateGo = strings.ReplaceAll(templateGo, "varScan", query.VarScan)
-	templateGo = strings.ReplaceAll(templateGo, "responseStruct", query.ResponseStruct)
+	var (
+		data = helper.NewTemplateData(cfg.Project, s, table)
+		repo = "storage.txt"
+	)
 
-	err = helper.WriteFile("generates/storage/"+tableName+".go", templateGo)
+	sides, err := data.Join()
 	if err != nil {
-		log.Println("Error while WriteFile:", err.Error())
+		log.Println("Error while Join:", err.Error())
 		return err
 	}
+	if sides != nil {
+		repo = "join_storage_repo.txt"
+	}
 
-	var storageRepoFilename = "./storage/storage.txt"
-	storageRepoBody, err := helper.ReadFile(storageRepoFilename)
+	storageRepo, err := helper.RenderTemplate(filepath.Join(cfg.TemplateDir, "storage", repo), data)
 	if err != nil {
-		log.Println("Error while ReadFile:", err.Error())
+		log.Println("Error while RenderTemplate:", err.Error())
 		return err
 	}
-	var storageRepo = string(storageRepoBody)
-
-	storageRepo = strings.ReplaceAll(storageRepo, "Template", upperHeadTableName)
-	storageRepoTexts += storageRepo + "\n"
+	storageRepoTexts[data.Group.StoragePackage] += storageRepo + "\n"
+
+	for _, foreignKey := range table.UnindexedForeignKeys() {
+		foreignKeyIndexes = append(foreignKeyIndexes, foreignKeyIndex{
+			Name:    table.Name + "_" + strings.Join(foreignKey.Columns, "_") + "_idx",
+			Table:   table.Name,
+			Columns: strings.Join(foreignKey.Columns, ", "),
+		})
+	}
 
 	return nil
 }
 
-func generateQuery(tableName string, fields []string) Query {
-	insertQuery := fmt.Sprintf(`INSERT INTO "%s" (`, tableName) + "\n"
-	insertValueQuery := "\tVALUES ("
-	var insertExecField, getQuery, updateQuery, updateExecQuery, varNullString, varScan, responseStruct string
-	for ind, field := range fields {
-		var (
-			fieldParse = strings.Split(field, ":")
-			fieldType  = helper.SQLToGoType(fieldParse[1])
-		)
-		field = fieldParse[0]
-
-		if field == "created_at" {
+func makeEnums(cfg config.GenerateConfig, w *writer.Writer, data helper.TemplateData) error {
+
+	for _, enum := range data.Enums() {
+		var filename = filepath.Join("storage", data.Group.StoragePackage, enum.Name+".go")
+		if enumFiles[filename] {
 			continue
 		}
+		enumFiles[filename] = true
 
-		var text = fmt.Sprintf("\t\t\t%s,\n", field)
-		if field == "updated_at" {
-			text = "\t\t\tupdated_at\n"
+		data.Enum = enum
+		templateGo, err := helper.RenderTemplate(filepath.Join(cfg.TemplateDir, "storage", "enum.txt"), data)
+		if err != nil {
+			log.Println("Error while RenderTemplate:", err.Error())
+			return err
 		}
-		insertQuery += text
 
-		if field != "updated_at" {
-			getQuery += "\t\t\t" + field + ",\n"
-			insertValueQuery += fmt.Sprintf("$%d, ", ind+1)
+		err = w.WriteFile(filename, templateGo)
+		if err != nil {
+			log.Println("Error while WriteFile:", err.Error())
+			return err
 		}
+	}
 
-		var unsupportedField = []string{"id", "updated_at"}
-		if !helper.Contains(unsupportedField, field) {
-			var (
-				fieldCamelCase = helper.SnakeToCamel(field)
-				fieldUpperHead = strings.ToUpper(string(fieldCamelCase[0])) + fieldCamelCase[1:]
-			)
-
-			insertExecField += fmt.Sprintf("\t\treq.Get%s(),\n", fieldUpperHead)
-			updateQuery += fmt.Sprintf("\t\t\t%s = :%s,\n", field, field)
-			updateExecQuery += "\t\t" + fmt.Sprintf(`"%s": req.Get%s(),`, field, fieldUpperHead) + "\n"
-
-			switch fieldType {
-			case "double":
-				varNullString += fmt.Sprintf("\t\t%s sql.NullFloat64\n", fieldCamelCase)
-				responseStruct += fmt.Sprintf("\t\t%s: %s.Float64,\n", fieldUpperHead, fieldCamelCase)
-			case "int64":
-				varNullString += fmt.Sprintf("\t\t%s sql.NullInt64\n", fieldCamelCase)
-				responseStruct += fmt.Sprintf("\t\t%s: %s.Int64,\n", fieldUpperHead, fieldCamelCase)
-			default:
-				varNullString += fmt.Sprintf("\t\t%s sql.NullString\n", fieldCamelCase)
-				responseStruct += fmt.Sprintf("\t\t%s: %s.String,\n", fieldUpperHead, fieldCamelCase)
-			}
-
-			varScan += fmt.Sprintf("\t\t&%s,\n", fieldCamelCase)
-		}
+	return nil
+}
+
+// MakeStorageRepo writes the repo interfaces of every storage package to
+// its storage.go.
+func MakeStorageRepo(w *writer.Writer) error {
+
+	var packages []string
+	for storagePackage := range storageRepoTexts {
+		packages = append(packages, storagePackage)
 	}
-	insertQuery += "\t\t)\n" + "\t" + insertValueQuery + "now())"
-	insertExecField = insertExecField[:len(insertExecField)-1]
-	getQuery = getQuery[:len(getQuery)-1]
-	updateQuery = updateQuery[:len(updateQuery)-1]
-	updateExecQuery = updateExecQuery[:len(updateExecQuery)-1]
-	varNullString = varNullString[:len(varNullString)-1]
-	varScan = varScan[:len(varScan)-1]
-	responseStruct = responseStruct[:len(responseStruct)-1]
-
-	return Query{
-		InsertQuery:     insertQuery,
-		InsertExecField: insertExecField,
-		GetQuery:        getQuery,
-		UpdateQuery:     updateQuery,
-		UpdateExecQuery: updateExecQuery,
-		VarNullString:   varNullString,
-		VarScan:         varScan,
-		ResponseStruct:  responseStruct,
+	sort.Strings(packages)
+
+	for _, storagePackage := range packages {
+		err := w.WriteFile(filepath.Join("storage", storagePackage, "storage.go"), storageRepoTexts[storagePackage])
+		if err != nil {
+			log.Println("Error while WriteFile:", err.Error())
+			return err
+		}
 	}
+
+	return nil
 }
 
-func MakeStorageRepo() error {
+func MakeForeignKeyIndexes(cfg config.GenerateConfig, w *writer.Writer) error {
+
+	if len(foreignKeyIndexes) == 0 {
+		return nil
+	}
+
+	migration, err := helper.RenderTemplate(filepath.Join(cfg.TemplateDir, "storage", "indexes.sql"), foreignKeyIndexes)
+	if err != nil {
+		log.Println("Error while RenderTemplate:", err.Error())
+		return err
+	}
 
-	err := helper.WriteFile("./generates/storage/storage.go", storageRepoTexts)
+	err = w.WriteFile(filepath.Join("migrations", "foreign_key_indexes.up.sql"), migration)
 	if err != nil {
 		log.Println("Error while WriteFile:", err.Error())
 		return err
@@ -195,14 +196,3 @@ func MakeStorageRepo() error {
 
 	return nil
 }
-
-type Query struct {
-	InsertQuery     string
-	InsertExecField string
-	GetQuery        string
-	UpdateQuery     string
-	UpdateExecQuery string
-	VarNullString   string
-	VarScan         string
-	ResponseStruct  string
-}